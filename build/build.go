@@ -4,11 +4,17 @@
 
 package build
 
-import "github.com/r3labs/graph"
+import (
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/merge"
+	"github.com/r3labs/graph"
+)
 
 // Build ...
 type Build struct {
-	ID         string       `json:"id"`
-	Definition string       `json:"definition"`
-	Mapping    *graph.Graph `json:"mapping"`
+	ID         string           `json:"id"`
+	Definition string           `json:"definition"`
+	Mapping    *graph.Graph     `json:"mapping"`
+	Merge      *merge.Report    `json:"merge,omitempty"`
+	Warnings   libmapper.Errors `json:"warnings,omitempty"`
 }
@@ -0,0 +1,801 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/cost"
+	"github.com/ernestio/definition-mapper/drift"
+	"github.com/ernestio/definition-mapper/handlers"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/merge"
+	"github.com/ernestio/definition-mapper/policy"
+	"github.com/ernestio/definition-mapper/replay"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/r3labs/graph"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const usage = `usage: definition-mapper <command> [options]
+
+Runs the mapper as a service when no command is given.
+
+commands:
+  plan             map a definition, optionally against a previous mapping
+  diff             diff two mappings
+  import-complete  convert a completed import graph into a build
+  validate         validate a definition
+  cost             estimate the monthly cost of a definition or mapping
+  drift            compare a stored mapping against a completed import of its service
+  terraform        export a mapping or definition as terraform configuration
+  schema           print the json schema of a provider's definition format
+  capabilities     print the providers and operations supported by the mapper
+  replay           replay captured requests, comparing their output with golden files
+`
+
+// cliCommands : offline commands that can be run without a running ernest stack
+var cliCommands = map[string]func([]string, io.Writer) error{
+	"plan":            planCommand,
+	"diff":            diffCommand,
+	"import-complete": importCompleteCommand,
+	"validate":        validateCommand,
+	"cost":            costCommand,
+	"drift":           driftCommand,
+	"terraform":       terraformCommand,
+	"schema":          schemaCommand,
+	"capabilities":    capabilitiesCommand,
+	"replay":          replayCommand,
+}
+
+// RunCLI : runs an offline command, returning the process exit code
+func RunCLI(args []string, out io.Writer) int {
+	cmd, ok := cliCommands[args[0]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	err := cmd(args[1:], out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error: "+err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func planCommand(args []string, out io.Writer) error {
+	var g *graph.Graph
+
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	definition := fs.String("definition", "", "definition file (yaml or json)")
+	mapping := fs.String("mapping", "", "previous mapping file (json)")
+	credentials := fs.String("credentials", "", "credentials file (yaml or json)")
+	name := fs.String("name", "", "service name, defaults to '<project>/<name>' from the definition")
+	changelog := fs.Bool("changelog", false, "include a changelog on the returned changes")
+	destroy := fs.Bool("destroy", false, "plan the removal of all components of the previous mapping")
+	strict := fs.Bool("strict", false, "reject definitions containing unknown keys")
+	vars := variableFlags(fs)
+	modules := fs.String("modules", "", "directory of module sources, named after their file")
+	format := fs.String("format", "json", "output format: json (the mapping), text or markdown")
+	secrets := fs.String("secrets", "", "secrets file (yaml or json), secrets are otherwise resolved from "+libmapper.SECRETPREFIX+" environment variables")
+	prices := fs.String("prices", "", "price table file (yaml or json) used to estimate the change in cost, instead of the bundled one")
+	policies := fs.String("policies", "", "policy rules file (yaml or json), evaluated along with any set by "+policy.POLICIESFILE)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := newCLIRequest(*definition, *credentials, *name)
+	if err != nil {
+		return err
+	}
+
+	r.Variables, err = vars.values()
+	if err != nil {
+		return err
+	}
+
+	r.Modules, err = readModules(*modules)
+	if err != nil {
+		return err
+	}
+
+	r.Changelog = *changelog
+	r.Strict = *strict
+
+	if *secrets != "" {
+		r.Secrets, err = libmapper.NewFileSecretResolver(*secrets)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *mapping != "" {
+		r.From, err = readMap(*mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *prices != "" {
+		r.Prices, err = cost.LoadPrices(*prices)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *policies != "" {
+		r.Policies, err = policy.Load(*policies)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *destroy && r.From == nil {
+		return errors.New("a previous mapping is required to plan a removal")
+	}
+
+	if *format != "json" {
+		if *destroy {
+			r.Definition = nil
+		}
+		return writePlan(out, r, *format)
+	}
+
+	switch {
+	case *destroy:
+		g, err = handlers.Delete(r)
+	case r.From != nil:
+		g, err = handlers.Update(r)
+	default:
+		g, err = handlers.Create(r)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	writeErrors(os.Stderr, "warning", r.Warnings)
+
+	if r.PolicyResult != nil {
+		writeViolations(os.Stderr, r.PolicyResult.Violations)
+	}
+
+	return writeGraph(out, g, libmapper.RedactChangelogs)
+}
+
+// writePlan : writes the plan of a request, rendered in the given format
+func writePlan(out io.Writer, r *request.Request, format string) error {
+	p, err := handlers.Plan(r)
+	if err != nil {
+		return err
+	}
+
+	output, err := p.Render(format)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, output)
+
+	return err
+}
+
+func diffCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	from := fs.String("from", "", "original mapping file (json)")
+	to := fs.String("to", "", "updated mapping file (json)")
+	credentials := fs.String("credentials", "", "credentials file (yaml or json)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := newCLIRequest("", *credentials, "")
+	if err != nil {
+		return err
+	}
+
+	r.From, err = readMap(*from)
+	if err != nil {
+		return err
+	}
+
+	r.To, err = readMap(*to)
+	if err != nil {
+		return err
+	}
+
+	g, err := handlers.Diff(r)
+	if err != nil {
+		return err
+	}
+
+	return writeGraph(out, g, libmapper.RedactGraph)
+}
+
+func importCompleteCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("import-complete", flag.ContinueOnError)
+	mapping := fs.String("mapping", "", "completed import graph file (json)")
+	definition := fs.String("definition", "", "existing definition file (yaml or json) to merge imported components into")
+	existing := fs.String("existing", "", "existing mapping file (json) to merge imported components into, required along with -definition")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ig, err := readMap(*mapping)
+	if err != nil {
+		return err
+	}
+
+	if *definition != "" {
+		data, err := ioutil.ReadFile(*definition)
+		if err != nil {
+			return err
+		}
+
+		if *existing == "" {
+			return errors.New("the existing mapping (-existing) must be set to merge into a definition")
+		}
+
+		em, err := readMap(*existing)
+		if err != nil {
+			return err
+		}
+
+		handlers.SetMergeTarget(ig, string(data), em)
+	}
+
+	b, err := handlers.ImportComplete(ig)
+	if err != nil {
+		return err
+	}
+
+	writeErrors(os.Stderr, "warning", b.Warnings)
+
+	if b.Merge != nil {
+		for _, c := range b.Merge.Conflicts {
+			fmt.Fprintf(os.Stderr, "warning: %s conflicts with the imported entry on: %s\n", conflictName(c), strings.Join(c.Fields, ", "))
+		}
+
+		if b.Merge.Rewritten {
+			fmt.Fprintln(os.Stderr, "warning: the existing definition could not be added to, so has been rewritten")
+		}
+	}
+
+	_, err = fmt.Fprint(out, b.Definition)
+
+	return err
+}
+
+func validateCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	definition := fs.String("definition", "", "definition file (yaml or json)")
+	credentials := fs.String("credentials", "", "credentials file (yaml or json)")
+	strict := fs.Bool("strict", false, "treat unknown keys as errors")
+	vars := variableFlags(fs)
+	modules := fs.String("modules", "", "directory of module sources, named after their file")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := newCLIRequest(*definition, *credentials, "")
+	if err != nil {
+		return err
+	}
+
+	r.Variables, err = vars.values()
+	if err != nil {
+		return err
+	}
+
+	r.Modules, err = readModules(*modules)
+	if err != nil {
+		return err
+	}
+
+	r.Strict = *strict
+
+	v, err := handlers.Validate(r)
+	if err != nil {
+		return err
+	}
+
+	writeErrors(out, "warning", v.Warnings)
+	writeErrors(out, "error", v.Errors)
+
+	if v.Valid {
+		_, err = fmt.Fprintln(out, "definition is valid")
+		return err
+	}
+
+	return fmt.Errorf("definition has %d errors", len(v.Errors))
+}
+
+func costCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("cost", flag.ContinueOnError)
+	definition := fs.String("definition", "", "definition file (yaml or json)")
+	mapping := fs.String("mapping", "", "mapping file (json), estimated when no definition is given")
+	credentials := fs.String("credentials", "", "credentials file (yaml or json)")
+	prices := fs.String("prices", "", "price table file (yaml or json), instead of the bundled one")
+	format := fs.String("format", "text", "output format: text or json")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := newCLIRequest(*definition, *credentials, "")
+	if err != nil {
+		return err
+	}
+
+	if *mapping != "" {
+		r.From, err = readMap(*mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *prices != "" {
+		r.Prices, err = cost.LoadPrices(*prices)
+		if err != nil {
+			return err
+		}
+	}
+
+	e, err := handlers.Cost(r)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		data, err := json.MarshalIndent(e, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(out, string(data))
+
+		return err
+	}
+
+	for _, c := range e.Components {
+		fmt.Fprintf(out, "%-40s %12.2f  %s\n", c.ComponentID, c.Monthly, c.Detail)
+	}
+
+	for _, id := range e.Unpriced {
+		fmt.Fprintf(out, "%-40s %12s\n", id, "not priced")
+	}
+
+	_, err = fmt.Fprintf(out, "%-40s %12.2f  %s/month\n", "total", e.Monthly, e.Currency)
+
+	return err
+}
+
+func terraformCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("terraform", flag.ContinueOnError)
+	definition := fs.String("definition", "", "definition file (yaml or json)")
+	mapping := fs.String("mapping", "", "mapping file (json), exported when no definition is given")
+	credentials := fs.String("credentials", "", "credentials file (yaml or json)")
+	format := fs.String("format", "hcl", "output format: hcl or json")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := newCLIRequest(*definition, *credentials, "")
+	if err != nil {
+		return err
+	}
+
+	if *mapping != "" {
+		r.From, err = readMap(*mapping)
+		if err != nil {
+			return err
+		}
+	}
+
+	e, err := handlers.Terraform(r)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		data, err := json.MarshalIndent(e, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(out, string(data))
+
+		return err
+	}
+
+	for _, id := range e.Skipped {
+		fmt.Fprintf(os.Stderr, "warning: %s cannot be exported to terraform\n", id)
+	}
+
+	_, err = fmt.Fprint(out, e.HCL())
+
+	return err
+}
+
+func driftCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("drift", flag.ContinueOnError)
+	mapping := fs.String("mapping", "", "stored mapping file (json)")
+	imported := fs.String("import", "", "completed import graph file (json)")
+	credentials := fs.String("credentials", "", "credentials file (yaml or json)")
+	name := fs.String("name", "", "service name, used to find unmanaged resources, defaults to the name of the stored mapping")
+	format := fs.String("format", "text", "output format: text or json")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := newCLIRequest("", *credentials, *name)
+	if err != nil {
+		return err
+	}
+
+	r.From, err = readMap(*mapping)
+	if err != nil {
+		return err
+	}
+
+	r.To, err = readMap(*imported)
+	if err != nil {
+		return err
+	}
+
+	d, err := handlers.Drift(r)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(out, string(data))
+
+		return err
+	}
+
+	if !d.Drifted {
+		_, err = fmt.Fprintln(out, "no drift detected")
+		return err
+	}
+
+	fmt.Fprintf(out, "Drift: %d changed, %d deleted, %d unmanaged\n", d.Summary[drift.DRIFTCHANGED], d.Summary[drift.DRIFTDELETED], d.Summary[drift.DRIFTUNMANAGED])
+
+	for _, kind := range drift.DRIFTS {
+		changes := d.ByDrift(kind)
+		if len(changes) < 1 {
+			continue
+		}
+
+		fmt.Fprintf(out, "\n%s:\n", kind)
+
+		for _, c := range changes {
+			fmt.Fprintf(out, "  %s (%s)\n", c.ComponentID, c.ProviderID)
+
+			for _, f := range c.Fields {
+				fmt.Fprintf(out, "      %s: %v => %v\n", f.Field, f.From, f.To)
+			}
+		}
+	}
+
+	return nil
+}
+
+func schemaCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	provider := fs.String("provider", "", "provider type (aws, azure or vcloud)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := handlers.Schema(*provider)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, string(data))
+
+	return err
+}
+
+func capabilitiesCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("capabilities", flag.ContinueOnError)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := handlers.Capabilities(version, OPERATIONS)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, string(data))
+
+	return err
+}
+
+func replayCommand(args []string, out io.Writer) error {
+	var failed int
+
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	capture := fs.String("capture", "", "captured requests file (json, one case per line)")
+	golden := fs.String("golden", "", "directory of golden files, named after each case")
+	update := fs.Bool("update", false, "record the output of each case as its golden file")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *capture == "" || *golden == "" {
+		return errors.New("a capture file and golden file directory are required")
+	}
+
+	cases, err := replay.Load(*capture)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cases {
+		err = replay.Replay(c, *golden, *update)
+
+		switch {
+		case err != nil:
+			failed++
+			fmt.Fprintf(out, "FAIL %s: %s\n", c.Name, err.Error())
+		case *update:
+			fmt.Fprintf(out, "updated %s\n", c.Name)
+		default:
+			fmt.Fprintf(out, "ok %s\n", c.Name)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d cases failed", failed, len(cases))
+	}
+
+	return nil
+}
+
+func writeErrors(out io.Writer, level string, errs libmapper.Errors) {
+	for _, verr := range errs {
+		if verr.Field != "" {
+			fmt.Fprintf(out, "%s: %s (%s)\n", level, verr.Error(), verr.Field)
+		} else {
+			fmt.Fprintf(out, "%s: %s\n", level, verr.Error())
+		}
+	}
+}
+
+// conflictName : returns the name of a conflicting definition entry, as shown to users
+func conflictName(c merge.Conflict) string {
+	if c.Name == "" {
+		return "the definition"
+	}
+
+	return c.Key + " '" + c.Name + "'"
+}
+
+// writeViolations : writes policy violations that did not prevent a mapping being returned
+func writeViolations(out io.Writer, violations []policy.Violation) {
+	for _, v := range violations {
+		fmt.Fprintf(out, "%s: policy '%s': %s: %s\n", v.Severity, v.Rule, v.ComponentID, v.Message)
+	}
+}
+
+// cliVariables : definition variable overrides set by the -var and -var-file flags
+type cliVariables struct {
+	file      string
+	overrides map[string]interface{}
+}
+
+func variableFlags(fs *flag.FlagSet) *cliVariables {
+	v := cliVariables{overrides: make(map[string]interface{})}
+
+	fs.Var(&v, "var", "set a definition variable, as name=value. Can be repeated")
+	fs.StringVar(&v.file, "var-file", "", "definition variables file (yaml or json)")
+
+	return &v
+}
+
+// String : returns the variables set on the command line
+func (v *cliVariables) String() string {
+	return fmt.Sprint(v.overrides)
+}
+
+// Set : sets a variable from a name=value pair
+func (v *cliVariables) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return errors.New("variables must be set as name=value")
+	}
+
+	v.overrides[parts[0]] = parts[1]
+
+	return nil
+}
+
+// values : returns the variables from the variable file, with any set on the command line taking precedence
+func (v *cliVariables) values() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if v.file != "" {
+		fv, err := readMap(v.file)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, x := range fv {
+			values[k] = x
+		}
+	}
+
+	for k, x := range v.overrides {
+		values[k] = x
+	}
+
+	return values, nil
+}
+
+// newCLIRequest : builds a request from the definition and credentials files
+func newCLIRequest(definition, credentials, name string) (*request.Request, error) {
+	var err error
+	var r request.Request
+
+	if credentials == "" {
+		return nil, errors.New("a credentials file must be specified")
+	}
+
+	r.Credentials, err = readMap(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if definition != "" {
+		r.Definition, err = readMap(definition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.Name = name
+	if r.Name == "" {
+		project, _ := r.Definition["project"].(string)
+		service, _ := r.Definition["name"].(string)
+		r.Name = project + "/" + service
+	}
+
+	if _, ok := r.Credentials["name"]; !ok {
+		r.Credentials["name"] = r.Name
+	}
+
+	return &r, nil
+}
+
+// readModules : reads all yaml and json files of a directory as module sources
+func readModules(dir string) (map[string]interface{}, error) {
+	modules := make(map[string]interface{})
+
+	if dir == "" {
+		return modules, nil
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if f.IsDir() || ext != ".yml" && ext != ".yaml" && ext != ".json" {
+			continue
+		}
+
+		m, err := readMap(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		modules[strings.TrimSuffix(f.Name(), ext)] = m
+	}
+
+	return modules, nil
+}
+
+// readMap : reads a yaml or json file into a generic map
+func readMap(path string) (map[string]interface{}, error) {
+	var m interface{}
+
+	if path == "" {
+		return nil, errors.New("no file specified")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(data, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	mm, ok := normalize(m).(map[string]interface{})
+	if !ok {
+		return nil, errors.New("could not load '" + path + "': expected a map")
+	}
+
+	return mm, nil
+}
+
+// normalize : converts yaml's map[interface{}]interface{} to json compatible types
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			m[fmt.Sprint(k)] = normalize(v)
+		}
+		return m
+	case []interface{}:
+		for i := range x {
+			x[i] = normalize(x[i])
+		}
+	}
+
+	return v
+}
+
+// writeGraph : writes a graph, encoded so any sensitive values shown to users are redacted
+func writeGraph(out io.Writer, g *graph.Graph, encode func(*graph.Graph) ([]byte, error)) error {
+	var buf bytes.Buffer
+
+	data, err := encode(g)
+	if err != nil {
+		return err
+	}
+
+	err = json.Indent(&buf, data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, buf.String())
+
+	return err
+}
@@ -0,0 +1,247 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cost
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	aws "github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	azure "github.com/ernestio/definition-mapper/libmapper/providers/azure/components"
+	"github.com/r3labs/graph"
+)
+
+// PROVIDERS : the providers whose components are priced
+var PROVIDERS = []string{"aws", "azure"}
+
+// Estimate : the estimated monthly cost of all components of a graph
+type Estimate struct {
+	Currency   string          `json:"currency"`
+	Monthly    float64         `json:"monthly"`
+	Components []ComponentCost `json:"components"`
+	Unpriced   []string        `json:"unpriced,omitempty"`
+}
+
+// ComponentCost : the estimated monthly cost of a single component
+type ComponentCost struct {
+	ComponentID   string  `json:"component_id"`
+	ComponentType string  `json:"component_type"`
+	Monthly       float64 `json:"monthly"`
+	Detail        string  `json:"detail"`
+}
+
+// Delta : the change in monthly cost between two graphs
+type Delta struct {
+	Currency string   `json:"currency"`
+	Before   float64  `json:"before"`
+	After    float64  `json:"after"`
+	Delta    float64  `json:"delta"`
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+// Supported : returns true if components of a provider are priced
+func Supported(provider string) bool {
+	for _, p := range PROVIDERS {
+		if p == provider {
+			return true
+		}
+	}
+
+	return false
+}
+
+// New : estimates the monthly cost of a graph. Components that are billed,
+// but whose size or type is not part of the price table, are listed as
+// unpriced. Components that are free or billed on usage are not listed
+func New(g *graph.Graph, p *Prices) *Estimate {
+	e := Estimate{
+		Currency:   p.Currency,
+		Components: []ComponentCost{},
+	}
+
+	for _, c := range g.Components {
+		cc, billed, ok := componentCost(c, p)
+		if !billed {
+			continue
+		}
+
+		if !ok {
+			e.Unpriced = append(e.Unpriced, c.GetID())
+			continue
+		}
+
+		e.Components = append(e.Components, cc)
+		e.Monthly += cc.Monthly
+	}
+
+	sort.Slice(e.Components, func(i, j int) bool {
+		return e.Components[i].ComponentID < e.Components[j].ComponentID
+	})
+
+	sort.Strings(e.Unpriced)
+
+	e.Monthly = round(e.Monthly)
+
+	return &e
+}
+
+// Compare : returns the change in monthly cost from one graph to another
+func Compare(from, to *graph.Graph, p *Prices) *Delta {
+	before := New(from, p)
+	after := New(to, p)
+
+	return &Delta{
+		Currency: p.Currency,
+		Before:   before.Monthly,
+		After:    after.Monthly,
+		Delta:    round(after.Monthly - before.Monthly),
+		Unpriced: unpriced(before.Unpriced, after.Unpriced),
+	}
+}
+
+// String : renders the change in cost as text
+func (d *Delta) String() string {
+	s := fmt.Sprintf("%s/month => %s/month (%s)", amount(d.Before, d.Currency), amount(d.After, d.Currency), signed(d.Delta, d.Currency))
+
+	if len(d.Unpriced) > 0 {
+		s = s + fmt.Sprintf(", %d components not priced", len(d.Unpriced))
+	}
+
+	return s
+}
+
+// componentCost : returns the monthly cost of a component, whether it is
+// billed at all and whether it could be priced from the price table
+func componentCost(c graph.Component, p *Prices) (ComponentCost, bool, bool) {
+	cc := ComponentCost{
+		ComponentID:   c.GetID(),
+		ComponentType: c.GetType(),
+	}
+
+	var ok bool
+
+	switch x := c.(type) {
+	case *aws.Instance:
+		cc.Monthly, ok = hourly(p.AWS.Instances, x.Type)
+		cc.Detail = x.Type
+	case *aws.EBSVolume:
+		var size, iops int64
+		if x.Size != nil {
+			size = *x.Size
+		}
+		if x.Iops != nil {
+			iops = *x.Iops
+		}
+
+		var price float64
+		price, ok = p.AWS.EBSVolumes[x.VolumeType]
+		cc.Monthly = price*float64(size) + p.AWS.EBSIops*float64(iops)
+		cc.Detail = fmt.Sprintf("%s, %d GB", x.VolumeType, size)
+		if iops > 0 {
+			cc.Detail = cc.Detail + fmt.Sprintf(", %d iops", iops)
+		}
+	case *aws.RDSInstance:
+		cc.Monthly, cc.Detail, ok = rdsCost(x, p)
+	case *aws.NatGateway:
+		cc.Monthly, ok = p.AWS.NatGateway*HOURSPERMONTH, true
+		cc.Detail = "nat gateway"
+	case *aws.ELB:
+		cc.Monthly, ok = p.AWS.ELB*HOURSPERMONTH, true
+		cc.Detail = "load balancer"
+	case *azure.VirtualMachine:
+		cc.Monthly, ok = hourly(p.Azure.VirtualMachines, x.VMSize)
+		cc.Detail = x.VMSize
+	case *azure.ManagedDisk:
+		var price float64
+		price, ok = p.Azure.ManagedDisks[x.StorageAccountType]
+		cc.Monthly = price * float64(x.DiskSizeGB)
+		cc.Detail = fmt.Sprintf("%s, %d GB", x.StorageAccountType, x.DiskSizeGB)
+	case *azure.SQLDatabase:
+		cc.Monthly, ok = p.Azure.SQLDatabases[x.RequestedServiceObjectiveName]
+		cc.Detail = x.RequestedServiceObjectiveName
+		if !ok {
+			cc.Monthly, ok = p.Azure.SQLDatabases[x.Edition]
+			cc.Detail = x.Edition
+		}
+	default:
+		return cc, false, false
+	}
+
+	cc.Monthly = round(cc.Monthly)
+
+	return cc, true, ok
+}
+
+// rdsCost : multi az instances are billed for both the instance and its standby.
+// Storage of instances within a cluster is billed on usage by the cluster
+func rdsCost(r *aws.RDSInstance, p *Prices) (float64, string, bool) {
+	monthly, ok := hourly(p.AWS.RDSInstances, r.Size)
+	detail := r.Size
+
+	if r.Cluster == "" && r.StorageSize != nil {
+		st := r.StorageType
+		if st == "" {
+			st = "gp2"
+		}
+
+		price, sok := p.AWS.RDSStorage[st]
+		ok = ok && sok
+
+		monthly = monthly + price*float64(*r.StorageSize)
+		detail = detail + fmt.Sprintf(", %s %d GB", st, *r.StorageSize)
+
+		if r.StorageIops != nil {
+			monthly = monthly + p.AWS.RDSIops*float64(*r.StorageIops)
+			detail = detail + fmt.Sprintf(", %d iops", *r.StorageIops)
+		}
+	}
+
+	if r.MultiAZ {
+		monthly = monthly * 2
+		detail = detail + ", multi az"
+	}
+
+	return monthly, detail, ok
+}
+
+// unpriced : returns the components left unpriced on either graph, as either
+// leaves the change in cost incomplete
+func unpriced(before, after []string) []string {
+	var ids []string
+
+	seen := make(map[string]bool)
+	for _, id := range append(append([]string{}, before...), after...) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func hourly(prices map[string]float64, size string) (float64, bool) {
+	price, ok := prices[size]
+	return price * HOURSPERMONTH, ok
+}
+
+func round(f float64) float64 {
+	return math.Floor(f*100+0.5) / 100
+}
+
+func amount(f float64, currency string) string {
+	return fmt.Sprintf("%.2f %s", f, currency)
+}
+
+func signed(f float64, currency string) string {
+	if f > 0 {
+		return "+" + amount(f, currency)
+	}
+
+	return amount(f, currency)
+}
@@ -0,0 +1,91 @@
+package cost_test
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/cost"
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// CostTestSuite : Test suite for cost estimates
+type CostTestSuite struct {
+	suite.Suite
+	Prices *cost.Prices
+}
+
+func size(s int64) *int64 {
+	return &s
+}
+
+// SetupTest : Setup test suite
+func (suite *CostTestSuite) SetupTest() {
+	suite.Prices = &cost.Prices{
+		Currency: "USD",
+		AWS: cost.AWSPrices{
+			Instances:    map[string]float64{"t2.micro": 0.01},
+			EBSVolumes:   map[string]float64{"gp2": 0.1},
+			RDSInstances: map[string]float64{"db.t2.micro": 0.02},
+			RDSStorage:   map[string]float64{"gp2": 0.1},
+			NatGateway:   0.05,
+		},
+	}
+}
+
+// TestNew : Testing the monthly cost of a graph
+func (suite *CostTestSuite) TestNew() {
+	g := graph.New()
+	for _, c := range []graph.Component{
+		&components.Instance{Name: "web-1", Type: "t2.micro"},
+		&components.Instance{Name: "web-2", Type: "x9.huge"},
+		&components.EBSVolume{Name: "data", VolumeType: "gp2", Size: size(20)},
+		&components.RDSInstance{Name: "db", Size: "db.t2.micro", StorageSize: size(10), MultiAZ: true},
+		&components.Vpc{Name: "vpc"},
+	} {
+		c.SetDefaultVariables()
+		g.AddComponent(c)
+	}
+
+	e := cost.New(g, suite.Prices)
+	suite.Equal("USD", e.Currency)
+	suite.Len(e.Components, 3)
+	suite.Equal("ebs_volume::data", e.Components[0].ComponentID)
+	suite.Equal(2.0, e.Components[0].Monthly)
+	suite.Equal(31.2, e.Components[2].Monthly)
+	suite.Equal([]string{"instance::web-2"}, e.Unpriced)
+	suite.Equal(2.0+7.3+31.2, e.Monthly)
+}
+
+// TestCompare : Testing the change in cost between two graphs
+func (suite *CostTestSuite) TestCompare() {
+	web := &components.Instance{Name: "web-1", Type: "t2.micro"}
+	web.SetDefaultVariables()
+
+	nat := &components.NatGateway{Name: "nat"}
+	nat.SetDefaultVariables()
+
+	// components removed from the graph are reported when they are not priced
+	removed := &components.Instance{Name: "web-2", Type: "x9.huge"}
+	removed.SetDefaultVariables()
+
+	from := graph.New()
+	from.AddComponent(web)
+	from.AddComponent(removed)
+
+	to := graph.New()
+	to.AddComponent(web)
+	to.AddComponent(nat)
+
+	d := cost.Compare(from, to, suite.Prices)
+	suite.Equal(7.3, d.Before)
+	suite.Equal(43.8, d.After)
+	suite.Equal(36.5, d.Delta)
+	suite.Equal([]string{"instance::web-2"}, d.Unpriced)
+	suite.Equal("7.30 USD/month => 43.80 USD/month (+36.50 USD), 1 components not priced", d.String())
+}
+
+// TestCostTestSuite : Test suite for cost estimates
+func TestCostTestSuite(t *testing.T) {
+	suite.Run(t, new(CostTestSuite))
+}
@@ -0,0 +1,224 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cost
+
+import (
+	"io/ioutil"
+	"os"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// PRICESFILE : the environment variable setting the price table used instead of the bundled one
+const PRICESFILE = "PRICES_FILE"
+
+// HOURSPERMONTH : the hours used to convert hourly prices to monthly prices
+const HOURSPERMONTH = 730
+
+// Prices : the price table costs are estimated from. Prices are on demand list
+// prices of a single reference region, so estimates are approximate
+type Prices struct {
+	Currency string      `json:"currency" yaml:"currency"`
+	Updated  string      `json:"updated" yaml:"updated"`
+	AWS      AWSPrices   `json:"aws" yaml:"aws"`
+	Azure    AzurePrices `json:"azure" yaml:"azure"`
+}
+
+// AWSPrices : aws prices, hourly for running resources and monthly for storage
+type AWSPrices struct {
+	Instances    map[string]float64 `json:"instances" yaml:"instances"`
+	EBSVolumes   map[string]float64 `json:"ebs_volumes" yaml:"ebs_volumes"`
+	EBSIops      float64            `json:"ebs_iops" yaml:"ebs_iops"`
+	RDSInstances map[string]float64 `json:"rds_instances" yaml:"rds_instances"`
+	RDSStorage   map[string]float64 `json:"rds_storage" yaml:"rds_storage"`
+	RDSIops      float64            `json:"rds_iops" yaml:"rds_iops"`
+	NatGateway   float64            `json:"nat_gateway" yaml:"nat_gateway"`
+	ELB          float64            `json:"elb" yaml:"elb"`
+}
+
+// AzurePrices : azure prices, hourly for virtual machines and monthly for disks and databases
+type AzurePrices struct {
+	VirtualMachines map[string]float64 `json:"virtual_machines" yaml:"virtual_machines"`
+	ManagedDisks    map[string]float64 `json:"managed_disks" yaml:"managed_disks"`
+	SQLDatabases    map[string]float64 `json:"sql_databases" yaml:"sql_databases"`
+}
+
+// DEFAULTPRICES : the bundled price table, in US dollars. AWS prices are for
+// us-east-1 and azure prices for East US, both on linux where it applies.
+// Instance and virtual machine prices are per hour, storage prices per GB
+// per month, provisioned iops per iops per month and sql databases per month,
+// keyed by service objective or, failing that, by edition
+var DEFAULTPRICES = Prices{
+	Currency: "USD",
+	Updated:  "2018-01-15",
+	AWS: AWSPrices{
+		Instances: map[string]float64{
+			"t2.nano":     0.0058,
+			"t2.micro":    0.0116,
+			"t2.small":    0.023,
+			"t2.medium":   0.0464,
+			"t2.large":    0.0928,
+			"t2.xlarge":   0.1856,
+			"t2.2xlarge":  0.3712,
+			"m4.large":    0.1,
+			"m4.xlarge":   0.2,
+			"m4.2xlarge":  0.4,
+			"m4.4xlarge":  0.8,
+			"m5.large":    0.096,
+			"m5.xlarge":   0.192,
+			"m5.2xlarge":  0.384,
+			"m5.4xlarge":  0.768,
+			"c4.large":    0.1,
+			"c4.xlarge":   0.199,
+			"c4.2xlarge":  0.398,
+			"c5.large":    0.085,
+			"c5.xlarge":   0.17,
+			"c5.2xlarge":  0.34,
+			"r4.large":    0.133,
+			"r4.xlarge":   0.266,
+			"r4.2xlarge":  0.532,
+			"i3.large":    0.156,
+			"i3.xlarge":   0.312,
+			"x1.16xlarge": 6.669,
+		},
+		EBSVolumes: map[string]float64{
+			"gp2":      0.1,
+			"io1":      0.125,
+			"st1":      0.045,
+			"sc1":      0.025,
+			"standard": 0.05,
+		},
+		EBSIops: 0.065,
+		RDSInstances: map[string]float64{
+			"db.t2.micro":    0.017,
+			"db.t2.small":    0.034,
+			"db.t2.medium":   0.068,
+			"db.t2.large":    0.136,
+			"db.t2.xlarge":   0.272,
+			"db.m4.large":    0.175,
+			"db.m4.xlarge":   0.35,
+			"db.m4.2xlarge":  0.7,
+			"db.m4.4xlarge":  1.401,
+			"db.r4.large":    0.24,
+			"db.r4.xlarge":   0.48,
+			"db.r4.2xlarge":  0.96,
+			"db.r4.4xlarge":  1.92,
+			"db.r3.large":    0.24,
+			"db.r3.xlarge":   0.475,
+			"db.r3.2xlarge":  0.945,
+			"db.r3.4xlarge":  1.89,
+			"db.r3.8xlarge":  3.78,
+			"db.m3.medium":   0.09,
+			"db.m3.large":    0.185,
+			"db.m3.xlarge":   0.365,
+			"db.m3.2xlarge":  0.73,
+			"db.r4.8xlarge":  3.84,
+			"db.r4.16xlarge": 7.68,
+		},
+		RDSStorage: map[string]float64{
+			"gp2":      0.115,
+			"io1":      0.125,
+			"standard": 0.1,
+		},
+		RDSIops:    0.1,
+		NatGateway: 0.045,
+		ELB:        0.025,
+	},
+	Azure: AzurePrices{
+		VirtualMachines: map[string]float64{
+			"Basic_A0":         0.018,
+			"Basic_A1":         0.023,
+			"Basic_A2":         0.079,
+			"Standard_A0":      0.02,
+			"Standard_A1":      0.06,
+			"Standard_A2":      0.12,
+			"Standard_A3":      0.24,
+			"Standard_A1_v2":   0.043,
+			"Standard_A2_v2":   0.091,
+			"Standard_A4_v2":   0.191,
+			"Standard_B1s":     0.0104,
+			"Standard_B1ms":    0.0207,
+			"Standard_B2s":     0.0416,
+			"Standard_B2ms":    0.0832,
+			"Standard_D1_v2":   0.057,
+			"Standard_D2_v2":   0.114,
+			"Standard_D3_v2":   0.229,
+			"Standard_D4_v2":   0.458,
+			"Standard_DS1_v2":  0.057,
+			"Standard_DS2_v2":  0.114,
+			"Standard_DS3_v2":  0.229,
+			"Standard_D2s_v3":  0.096,
+			"Standard_D4s_v3":  0.192,
+			"Standard_D8s_v3":  0.384,
+			"Standard_F2s":     0.1,
+			"Standard_F4s":     0.199,
+			"Standard_E2s_v3":  0.126,
+			"Standard_E4s_v3":  0.252,
+			"Standard_E8s_v3":  0.504,
+			"Standard_DS11_v2": 0.185,
+		},
+		ManagedDisks: map[string]float64{
+			"Standard_LRS": 0.045,
+			"Premium_LRS":  0.15,
+		},
+		SQLDatabases: map[string]float64{
+			"Basic":    4.99,
+			"Standard": 15.03,
+			"Premium":  465,
+			"S0":       15.03,
+			"S1":       30.05,
+			"S2":       75.13,
+			"S3":       150.26,
+			"P1":       465,
+			"P2":       930,
+			"P4":       1860,
+			"P6":       3720,
+		},
+	},
+}
+
+// LoadPrices : loads a price table from a yaml or json file
+func LoadPrices(path string) (*Prices, error) {
+	var p Prices
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// defaultPrices : the price table used by requests that do not set their own
+var defaultPrices = &DEFAULTPRICES
+
+// LoadDefaultPrices : loads the price table set by PRICES_FILE once, to be used
+// by every request that does not set its own. The bundled table is kept when
+// PRICES_FILE is not set or can not be loaded
+func LoadDefaultPrices() error {
+	path := os.Getenv(PRICESFILE)
+	if path == "" {
+		return nil
+	}
+
+	p, err := LoadPrices(path)
+	if err != nil {
+		return err
+	}
+
+	defaultPrices = p
+
+	return nil
+}
+
+// DefaultPrices : returns the price table used by requests that do not set their own
+func DefaultPrices() *Prices {
+	return defaultPrices
+}
@@ -0,0 +1,188 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package drift
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/plan"
+	"github.com/r3labs/graph"
+)
+
+const (
+	// DRIFTCHANGED : fields of the resource have been changed out of band
+	DRIFTCHANGED = "changed"
+	// DRIFTDELETED : the resource has been deleted out of band
+	DRIFTDELETED = "deleted"
+	// DRIFTUNMANAGED : the resource is tagged with the service, but is not part of its mapping
+	DRIFTUNMANAGED = "unmanaged"
+)
+
+// DRIFTS : all kinds of drift, in the order they are shown
+var DRIFTS = []string{DRIFTCHANGED, DRIFTDELETED, DRIFTUNMANAGED}
+
+// SERVICETAG : the tag identifying the service a resource was created for
+const SERVICETAG = "ernest.service"
+
+// Report : the differences between the last applied mapping of a service and
+// the resources found on the provider
+type Report struct {
+	Drifted bool           `json:"drifted"`
+	Summary map[string]int `json:"summary"`
+	Changes []Change       `json:"changes"`
+}
+
+// Change : a single resource that has drifted
+type Change struct {
+	Drift         string             `json:"drift"`
+	ComponentID   string             `json:"component_id"`
+	ComponentType string             `json:"component_type"`
+	ProviderID    string             `json:"provider_id,omitempty"`
+	Fields        []plan.FieldChange `json:"fields,omitempty"`
+}
+
+// New : compares a stored mapping against an import of the same service.
+// Components are aligned by provider id, falling back to their component id.
+// Field changes are reported from the stored to the imported value. Fields
+// that are not returned by the import are not treated as changed. Imported
+// components that are not aligned are only reported as unmanaged if they are
+// tagged with the given service name
+func New(stored, live *graph.Graph, service string) (*Report, error) {
+	r := Report{
+		Summary: make(map[string]int),
+		Changes: []Change{},
+	}
+
+	for _, d := range DRIFTS {
+		r.Summary[d] = 0
+	}
+
+	byProviderID := make(map[string]graph.Component)
+	for _, c := range live.Components {
+		if c.GetProviderID() != "" {
+			byProviderID[c.GetProviderID()] = c
+		}
+	}
+
+	aligned := make(map[graph.Component]bool)
+
+	for _, c := range stored.Components {
+		if c.GetType() == "credentials" {
+			continue
+		}
+
+		lc := byProviderID[c.GetProviderID()]
+		if lc == nil || c.GetProviderID() == "" {
+			lc = live.Component(c.GetID())
+		}
+
+		if lc == nil || lc.GetType() != c.GetType() {
+			r.add(change(DRIFTDELETED, c, nil))
+			continue
+		}
+
+		aligned[lc] = true
+
+		fields, err := plan.FieldChanges(c, lc)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = returned(lc, fields)
+		if len(fields) > 0 {
+			r.add(change(DRIFTCHANGED, c, fields))
+		}
+	}
+
+	for _, c := range live.Components {
+		if c.GetType() == "credentials" || aligned[c] {
+			continue
+		}
+
+		if service != "" && c.GetTag(SERVICETAG) == service {
+			r.add(change(DRIFTUNMANAGED, c, nil))
+		}
+	}
+
+	sort.SliceStable(r.Changes, func(i, j int) bool {
+		if r.Changes[i].Drift != r.Changes[j].Drift {
+			return driftIndex(r.Changes[i].Drift) < driftIndex(r.Changes[j].Drift)
+		}
+		return r.Changes[i].ComponentID < r.Changes[j].ComponentID
+	})
+
+	return &r, nil
+}
+
+// ByDrift : returns all changes of the given kind of drift
+func (r *Report) ByDrift(drift string) []Change {
+	var changes []Change
+
+	for _, c := range r.Changes {
+		if c.Drift == drift {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+func (r *Report) add(c Change) {
+	r.Changes = append(r.Changes, c)
+	r.Summary[c.Drift]++
+	r.Drifted = true
+}
+
+func change(drift string, c graph.Component, fields []plan.FieldChange) Change {
+	return Change{
+		Drift:         drift,
+		ComponentID:   c.GetID(),
+		ComponentType: c.GetType(),
+		ProviderID:    c.GetProviderID(),
+		Fields:        fields,
+	}
+}
+
+// returned : drops changes to sensitive fields that are not set on the imported
+// component, as imports do not return them, i.e. passwords. Other fields that
+// are not set are reported, as they may have been cleared out of band.
+// Component ids are dropped as well, as components aligned by provider id may
+// be named differently on import
+func returned(c graph.Component, fields []plan.FieldChange) []plan.FieldChange {
+	var changes []plan.FieldChange
+
+	unset := make(map[string]bool)
+
+	v := reflect.Indirect(reflect.ValueOf(c))
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			tag := strings.Split(v.Type().Field(i).Tag.Get("diff"), ",")[0]
+			if tag != "" && tag != "-" && plan.IsZero(v.Field(i)) {
+				unset[tag] = true
+			}
+		}
+	}
+
+	for _, f := range fields {
+		field := strings.Split(f.Field, ".")[0]
+		if !(f.Sensitive && unset[field]) && !strings.HasPrefix(field, "_") {
+			changes = append(changes, f)
+		}
+	}
+
+	return changes
+}
+
+func driftIndex(drift string) int {
+	for i, d := range DRIFTS {
+		if d == drift {
+			return i
+		}
+	}
+
+	return len(DRIFTS)
+}
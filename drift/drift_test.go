@@ -0,0 +1,92 @@
+package drift_test
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/drift"
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// DriftTestSuite : Test suite for drift reports
+type DriftTestSuite struct {
+	suite.Suite
+	Stored *graph.Graph
+	Live   *graph.Graph
+}
+
+func instance(name, awsID, itype string) *components.Instance {
+	return &components.Instance{
+		ComponentType:  components.TYPEINSTANCE,
+		ComponentID:    components.TYPEINSTANCE + components.TYPEDELIMITER + name,
+		Name:           name,
+		InstanceAWSID:  awsID,
+		Type:           itype,
+		Image:          "ami-1234",
+		SecurityGroups: []string{"web"},
+		Tags:           map[string]string{"Name": name, "ernest.service": "web"},
+	}
+}
+
+// SetupTest : Setup test suite
+func (suite *DriftTestSuite) SetupTest() {
+	suite.Stored = graph.New()
+	suite.Stored.AddComponent(instance("web-1", "i-1", "t2.micro"))
+	suite.Stored.AddComponent(instance("web-2", "i-2", "t2.micro"))
+	suite.Stored.AddComponent(instance("web-3", "i-3", "t2.micro"))
+	suite.Stored.AddComponent(&components.RDSInstance{ComponentType: components.TYPERDSINSTANCE, ComponentID: components.TYPERDSINSTANCE + components.TYPEDELIMITER + "db", Name: "db", Size: "db.t2.micro", DatabasePassword: "secret"})
+
+	renamed := instance("renamed", "i-2", "t2.micro")
+	renamed.Tags["Name"] = "web-2"
+	renamed.Image = ""
+	renamed.SecurityGroups = nil
+
+	other := instance("other", "i-5", "t2.micro")
+	other.Tags["ernest.service"] = "other"
+
+	suite.Live = graph.New()
+	suite.Live.AddComponent(instance("web-1", "i-1", "t2.large"))
+	suite.Live.AddComponent(renamed)
+	suite.Live.AddComponent(instance("web-4", "i-4", "t2.micro"))
+	suite.Live.AddComponent(other)
+	suite.Live.AddComponent(&components.RDSInstance{ComponentType: components.TYPERDSINSTANCE, ComponentID: components.TYPERDSINSTANCE + components.TYPEDELIMITER + "db", Name: "db", Size: "db.t2.micro"})
+}
+
+// TestNew : Testing drift between a stored mapping and an import
+func (suite *DriftTestSuite) TestNew() {
+	r, err := drift.New(suite.Stored, suite.Live, "web")
+	suite.Nil(err)
+	suite.True(r.Drifted)
+	suite.Equal(map[string]int{"changed": 2, "deleted": 1, "unmanaged": 1}, r.Summary)
+	suite.Len(r.Changes, 4)
+
+	suite.Equal(drift.DRIFTCHANGED, r.Changes[0].Drift)
+	suite.Equal("instance::web-1", r.Changes[0].ComponentID)
+	suite.Len(r.Changes[0].Fields, 1)
+	suite.Equal("instance_type", r.Changes[0].Fields[0].Field)
+	suite.Equal("t2.micro", r.Changes[0].Fields[0].From)
+	suite.Equal("t2.large", r.Changes[0].Fields[0].To)
+
+	// values cleared out of band are reported, passwords that imports do not return are not
+	suite.Equal("instance::web-2", r.Changes[1].ComponentID)
+	suite.Len(r.Changes[1].Fields, 1)
+	suite.Equal("security_groups.0", r.Changes[1].Fields[0].Field)
+
+	suite.Equal(drift.DRIFTDELETED, r.Changes[2].Drift)
+	suite.Equal("instance::web-3", r.Changes[2].ComponentID)
+
+	suite.Equal(drift.DRIFTUNMANAGED, r.Changes[3].Drift)
+	suite.Equal("instance::web-4", r.Changes[3].ComponentID)
+	suite.Equal("i-4", r.Changes[3].ProviderID)
+
+	r, err = drift.New(suite.Stored, suite.Stored, "web")
+	suite.Nil(err)
+	suite.False(r.Drifted)
+	suite.Len(r.Changes, 0)
+}
+
+// TestDriftTestSuite : Test suite for drift reports
+func TestDriftTestSuite(t *testing.T) {
+	suite.Run(t, new(DriftTestSuite))
+}
@@ -0,0 +1,88 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"sort"
+
+	"github.com/ernestio/definition-mapper/cost"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers"
+	"github.com/ernestio/definition-mapper/terraform"
+)
+
+// CapabilitiesResult : the providers and operations supported by the mapper
+type CapabilitiesResult struct {
+	Version   string                 `json:"version"`
+	Providers []ProviderCapabilities `json:"providers"`
+}
+
+// ProviderCapabilities : what the mapper supports for a single provider
+type ProviderCapabilities struct {
+	Name           string   `json:"name"`
+	Aliases        []string `json:"aliases"`
+	ComponentTypes []string `json:"component_types"`
+	DefinitionKeys []string `json:"definition_keys"`
+	Operations     []string `json:"operations"`
+}
+
+// Capabilities : handles a capabilities request, returning the component types
+// and definition keys of every registered provider, along with the operations
+// and version of the mapper
+func Capabilities(version string, operations []string) (*CapabilitiesResult, error) {
+	c := CapabilitiesResult{
+		Version:   version,
+		Providers: []ProviderCapabilities{},
+	}
+
+	for _, name := range providers.Providers() {
+		m, err := providers.NewMapper(name)
+		if err != nil {
+			return nil, err
+		}
+
+		p := ProviderCapabilities{
+			Name:           name,
+			Aliases:        providers.Aliases(name),
+			ComponentTypes: libmapper.ComponentTypes(m),
+			DefinitionKeys: []string{},
+			Operations:     providerOperations(name, operations),
+		}
+
+		if p.Aliases == nil {
+			p.Aliases = []string{}
+		}
+
+		properties, _ := libmapper.DefinitionSchema(m)["properties"].(map[string]interface{})
+		for key := range properties {
+			p.DefinitionKeys = append(p.DefinitionKeys, key)
+		}
+
+		sort.Strings(p.DefinitionKeys)
+
+		c.Providers = append(c.Providers, p)
+	}
+
+	return &c, nil
+}
+
+// providerOperations : returns the operations supported on a provider, as cost
+// estimates and terraform exports are limited to some providers
+func providerOperations(name string, operations []string) []string {
+	ops := []string{}
+
+	for _, op := range operations {
+		switch {
+		case op == "cost" && !cost.Supported(name):
+			continue
+		case op == "terraform" && !terraform.Supported(name):
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops
+}
@@ -0,0 +1,36 @@
+package handlers_test
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/handlers"
+	"github.com/stretchr/testify/suite"
+)
+
+// CapabilitiesTestSuite : Test suite for capabilities requests
+type CapabilitiesTestSuite struct {
+	suite.Suite
+}
+
+// TestCapabilities : Testing component types and operations are reported by provider
+func (suite *CapabilitiesTestSuite) TestCapabilities() {
+	c, err := handlers.Capabilities("1.0.0", []string{"create", "cost", "terraform"})
+	suite.Nil(err)
+
+	providers := make(map[string]handlers.ProviderCapabilities)
+	for _, p := range c.Providers {
+		providers[p.Name] = p
+	}
+
+	suite.Contains(providers["aws"].ComponentTypes, "iam_policy")
+	suite.NotContains(providers["aws"].ComponentTypes, "iam_policie")
+	suite.Contains(providers["azure"].ComponentTypes, "managed_disk")
+	suite.Equal([]string{"create", "cost", "terraform"}, providers["aws"].Operations)
+	suite.Equal([]string{"create", "cost", "terraform"}, providers["azure"].Operations)
+	suite.Equal([]string{"create"}, providers["vcloud"].Operations)
+}
+
+// TestCapabilitiesTestSuite : Test suite for capabilities requests
+func TestCapabilitiesTestSuite(t *testing.T) {
+	suite.Run(t, new(CapabilitiesTestSuite))
+}
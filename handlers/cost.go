@@ -0,0 +1,35 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"github.com/ernestio/definition-mapper/cost"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/r3labs/graph"
+)
+
+// Cost : handles a cost request, estimating the monthly cost of the definition.
+// If no definition is set, the cost of the previous mapping is estimated
+func Cost(r *request.Request) (*cost.Estimate, error) {
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
+	}
+
+	g := graph.New()
+
+	switch {
+	case r.Definition != nil:
+		g, err = r.DefinitionToGraph(m)
+	case r.From != nil:
+		g, err = r.FromMapping(m)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cost.New(g, r.PriceTable()), nil
+}
@@ -5,9 +5,6 @@
 package handlers
 
 import (
-	"errors"
-
-	"github.com/ernestio/definition-mapper/libmapper/providers"
 	"github.com/ernestio/definition-mapper/request"
 	"github.com/r3labs/graph"
 )
@@ -16,11 +13,9 @@ import (
 func Create(r *request.Request) (*graph.Graph, error) {
 	var g *graph.Graph
 
-	p := r.Provider()
-
-	m := providers.NewMapper(p)
-	if m == nil {
-		return nil, errors.New("could not infer environment provider type")
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
 	}
 
 	dg, err := r.DefinitionToGraph(m)
@@ -43,5 +38,11 @@ func Create(r *request.Request) (*graph.Graph, error) {
 	g.UserID = r.UserID
 	g.Username = r.Username
 
-	return g, err
+	// graphs violating a rule with a deny severity are never returned to be built
+	_, err = CheckPolicies(r, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
 }
@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/handlers"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/policy"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/stretchr/testify/suite"
+)
+
+// CreateTestSuite : Test suite for create requests
+type CreateTestSuite struct {
+	suite.Suite
+}
+
+func createRequest() *request.Request {
+	return &request.Request{
+		ID:          "build-1",
+		Name:        "acme/payments",
+		Credentials: map[string]interface{}{"type": "aws", "region": "eu-west-1"},
+		Definition: map[string]interface{}{
+			"name":    "payments",
+			"project": "acme",
+			"vpcs":    []interface{}{map[string]interface{}{"name": "main", "subnet": "10.0.0.0/16", "subnet_size": 24}},
+		},
+	}
+}
+
+// TestCreateWarnings : Testing unknown keys are returned as warnings, unless strict
+func (suite *CreateTestSuite) TestCreateWarnings() {
+	r := createRequest()
+
+	_, err := handlers.Create(r)
+	suite.Nil(err)
+	suite.Len(r.Warnings, 1)
+	suite.Equal(libmapper.ErrCodeUnknownKey, r.Warnings[0].Code)
+	suite.Equal(libmapper.SeverityWarning, r.Warnings[0].Severity)
+	suite.Equal("vpcs[0].subnet_size", r.Warnings[0].Field)
+
+	r = createRequest()
+	r.Strict = true
+
+	_, err = handlers.Create(r)
+	suite.NotNil(err)
+}
+
+// TestCreatePolicies : Testing graphs violating a deny rule are not returned
+func (suite *CreateTestSuite) TestCreatePolicies() {
+	rule := policy.Rule{Name: "vpc-range", Severity: policy.WARN, Component: "vpc", Assert: `subnet == "10.1.0.0/16"`}
+
+	r := createRequest()
+	r.Policies = []policy.Rule{rule}
+
+	g, err := handlers.Create(r)
+	suite.Nil(err)
+	suite.NotNil(g)
+	suite.True(r.PolicyResult.Passed)
+	suite.Len(r.PolicyResult.Violations, 1)
+
+	rule.Severity = policy.DENY
+
+	r = createRequest()
+	r.Policies = []policy.Rule{rule}
+
+	g, err = handlers.Create(r)
+	suite.NotNil(err)
+	suite.Nil(g)
+	suite.False(r.PolicyResult.Passed)
+}
+
+// TestCreateTestSuite : Test suite for create requests
+func TestCreateTestSuite(t *testing.T) {
+	suite.Run(t, new(CreateTestSuite))
+}
@@ -5,9 +5,6 @@
 package handlers
 
 import (
-	"errors"
-
-	"github.com/ernestio/definition-mapper/libmapper/providers"
 	"github.com/ernestio/definition-mapper/request"
 	"github.com/r3labs/graph"
 )
@@ -16,11 +13,9 @@ import (
 func Delete(r *request.Request) (*graph.Graph, error) {
 	var g *graph.Graph
 
-	p := r.Provider()
-
-	m := providers.NewMapper(p)
-	if m == nil {
-		return nil, errors.New("could not infer environment provider type")
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
 	}
 
 	original, err := r.FromMapping(m)
@@ -5,20 +5,15 @@
 package handlers
 
 import (
-	"errors"
-
-	"github.com/ernestio/definition-mapper/libmapper/providers"
 	"github.com/ernestio/definition-mapper/request"
 	"github.com/r3labs/graph"
 )
 
 // Diff : handles a diff request
 func Diff(r *request.Request) (*graph.Graph, error) {
-	p := r.Provider()
-
-	m := providers.NewMapper(p)
-	if m == nil {
-		return nil, errors.New("could not infer environment provider type")
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
 	}
 
 	fg, err := r.FromMapping(m)
@@ -0,0 +1,49 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"strings"
+
+	"github.com/ernestio/definition-mapper/drift"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/request"
+)
+
+// Drift : handles a drift request, comparing the last applied mapping (from)
+// against a completed import of the same service (to)
+func Drift(r *request.Request) (*drift.Report, error) {
+	if r.From == nil || r.To == nil {
+		return nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "drift requests must set both the stored mapping (from) and the import result (to)")
+	}
+
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := r.FromMapping(m)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := r.ToMapping(m)
+	if err != nil {
+		return nil, err
+	}
+
+	service := serviceName(r.Name)
+	if service == "" {
+		service = serviceName(stored.Name)
+	}
+
+	return drift.New(stored, live, service)
+}
+
+// serviceName : returns the name of a service from its '<project>/<service>' name
+func serviceName(name string) string {
+	parts := strings.SplitN(name, "/", 2)
+	return parts[len(parts)-1]
+}
@@ -8,29 +8,76 @@ import (
 	"strings"
 
 	"github.com/ernestio/definition-mapper/build"
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/ernestio/definition-mapper/libmapper/providers"
-	aws "github.com/ernestio/definition-mapper/libmapper/providers/aws/definition"
-	azure "github.com/ernestio/definition-mapper/libmapper/providers/azure/definition"
-	vcloud "github.com/ernestio/definition-mapper/libmapper/providers/vcloud/definition"
+	"github.com/ernestio/definition-mapper/logger"
+	"github.com/ernestio/definition-mapper/merge"
 	"github.com/ernestio/definition-mapper/request"
 	"github.com/r3labs/graph"
 	yaml "gopkg.in/yaml.v2"
 )
 
+const (
+	// MERGEBUILD : the key of an import graph's credentials holding the id of
+	// the build whose definition and mapping imported components are merged into
+	MERGEBUILD = "_merge_build"
+	// MERGEDEFINITION : the key of an import graph's credentials holding the
+	// text of the definition imported components are merged into
+	MERGEDEFINITION = "_merge_definition"
+	// MERGEMAPPING : the key of an import graph's credentials holding the
+	// mapping imported components are merged into
+	MERGEMAPPING = "_merge_mapping"
+	// IMPORTFILTERS : the key of an import graph's credentials holding the
+	// filters of the import, applied again to the components found
+	IMPORTFILTERS = "_import_filters"
+)
+
 // Import : handles a import request
 func Import(r *request.Request) (*graph.Graph, error) {
-	p := r.Provider()
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
+	}
 
-	m := providers.NewMapper(p)
+	filters := r.ImportFilters(m)
 
-	filters := r.ImportFilters()
+	if v, ok := m.(libmapper.ImportFilterValidator); ok {
+		errs := v.ValidateImportFilters(filters)
+		if len(errs) > 0 {
+			return nil, errs
+		}
+	}
+
+	r.Logger().Info("creating import graph", logger.Fields{"filters": filters})
 
 	ig := m.CreateImportGraph(filters)
 	ig.ID = r.ID
 	ig.Name = r.Name
 
 	c := m.ProviderCredentials(r.Credentials)
-	err := ig.AddComponent(c)
+
+	// the credentials are the only component not sent to a connector, so
+	// they carry the filters and merge target through to the completed import.
+	// Only the id of the merge target's build is carried, as the import graph
+	// is sent back to the requester and stored along with the build
+	gc, _ := c.(*graph.GenericComponent)
+
+	if gc != nil && len(filters) > 0 {
+		(*gc)[IMPORTFILTERS] = filters
+	}
+
+	if r.Merge != "" {
+		id, _ := r.From["id"].(string)
+		if id == "" {
+			return nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "merging imported components into a definition requires the service's current mapping (from)")
+		}
+
+		if gc != nil {
+			(*gc)[MERGEBUILD] = id
+		}
+	}
+
+	err = ig.AddComponent(c)
 	if err != nil {
 		return nil, err
 	}
@@ -48,17 +95,40 @@ func Import(r *request.Request) (*graph.Graph, error) {
 	return g, nil
 }
 
-// ImportComplete : handles the conversion of an import graph to a definition
+// ImportComplete : handles the conversion of an import graph to a definition.
+// If the service's current definition and mapping have been set on the import
+// graph, imported components are merged into both rather than replacing them
 func ImportComplete(ig map[string]interface{}) (*build.Build, error) {
 	provider := getGraphProvider(ig)
 
-	m := providers.NewMapper(provider)
+	id := MergeBuild(ig)
+	existing, mapping := mergeTarget(ig)
+	filters := importFilters(ig)
+
+	if id != "" && existing == "" {
+		return nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "the definition and mapping of build '"+id+"' imported components are merged into have not been loaded")
+	}
+
+	m, err := providers.NewMapper(provider)
+	if err != nil {
+		return nil, err
+	}
 
 	g, err := m.LoadGraph(ig)
 	if err != nil {
 		return nil, err
 	}
 
+	if fm, ok := m.(libmapper.ImportFilteringMapper); ok {
+		fm.FilterImport(g, filters)
+	}
+
+	var warnings libmapper.Errors
+
+	if gm, ok := m.(libmapper.GroupingMapper); ok {
+		warnings = gm.GroupComponents(g)
+	}
+
 	d, err := m.ConvertGraph(g)
 	if err != nil {
 		return nil, err
@@ -66,19 +136,9 @@ func ImportComplete(ig map[string]interface{}) (*build.Build, error) {
 
 	parts := strings.Split(g.Name, "/")
 
-	switch provider {
-	case "aws", "aws-fake":
-		def := d.(*aws.Definition)
-		def.Name = parts[1]
-		def.Project = parts[0]
-	case "azure", "azure-fake":
-		def := d.(*azure.Definition)
-		def.Name = parts[1]
-		def.Project = parts[0]
-	case "vcloud", "vcloud-fake":
-		def := d.(*vcloud.Definition)
-		def.Name = parts[1]
-		def.Project = parts[0]
+	if nd, ok := d.(libmapper.NamedDefinition); ok && len(parts) > 1 {
+		nd.SetName(parts[1])
+		nd.SetProject(parts[0])
 	}
 
 	data, err := yaml.Marshal(d)
@@ -90,17 +150,119 @@ func ImportComplete(ig map[string]interface{}) (*build.Build, error) {
 		ID:         g.ID,
 		Definition: string(data),
 		Mapping:    g,
+		Warnings:   warnings,
+	}
+
+	if existing == "" {
+		return &b, nil
+	}
+
+	if mapping == nil {
+		return nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "merging imported components into a definition requires the service's current mapping")
+	}
+
+	data, b.Merge, err = merge.Definitions([]byte(existing), data)
+	if err != nil {
+		return nil, libmapper.NewError(libmapper.ErrCodeInvalidDefinition, err.Error())
+	}
+
+	b.Definition = string(data)
+
+	// the stored mapping must hold the existing components along with those
+	// imported, otherwise the next apply would create the existing ones again
+	b.Mapping, err = m.LoadGraph(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Mapping.ID = g.ID
+
+	_, err = merge.Mappings(b.Mapping, g)
+	if err != nil {
+		return nil, err
 	}
 
-	return &b, err
+	return &b, nil
 }
 
-func getGraphProvider(m map[string]interface{}) string {
-	for _, c := range m["components"].([]interface{}) {
-		x := c.(map[string]interface{})
-		if x["_component"].(string) == "credentials" {
-			return x["_provider"].(string)
+// MergeBuild : returns the id of the build a completed import graph is
+// merged into, so its definition and mapping can be loaded and set on the
+// graph with SetMergeTarget. It is empty if the import is not merged
+func MergeBuild(ig map[string]interface{}) string {
+	id, _ := graphCredentials(ig)[MERGEBUILD].(string)
+	return id
+}
+
+// SetMergeTarget : sets the definition and mapping the components of a
+// completed import graph are merged into
+func SetMergeTarget(ig map[string]interface{}, definition string, mapping map[string]interface{}) {
+	c := graphCredentials(ig)
+	if c == nil {
+		return
+	}
+
+	c[MERGEDEFINITION] = definition
+	c[MERGEMAPPING] = mapping
+}
+
+// mergeTarget : returns the definition and mapping set on an import graph's
+// credentials, removing them so they are not stored on the mapping
+func mergeTarget(ig map[string]interface{}) (string, map[string]interface{}) {
+	c := graphCredentials(ig)
+	if c == nil {
+		return "", nil
+	}
+
+	definition, _ := c[MERGEDEFINITION].(string)
+	mapping, _ := c[MERGEMAPPING].(map[string]interface{})
+
+	delete(c, MERGEBUILD)
+	delete(c, MERGEDEFINITION)
+	delete(c, MERGEMAPPING)
+
+	return definition, mapping
+}
+
+// importFilters : returns the filters carried by an import graph's
+// credentials, removing them so they are not stored on the mapping
+func importFilters(ig map[string]interface{}) []string {
+	c := graphCredentials(ig)
+	if c == nil {
+		return nil
+	}
+
+	var filters []string
+
+	switch x := c[IMPORTFILTERS].(type) {
+	case []string:
+		filters = x
+	case []interface{}:
+		for _, f := range x {
+			if s, ok := f.(string); ok {
+				filters = append(filters, s)
+			}
+		}
+	}
+
+	delete(c, IMPORTFILTERS)
+
+	return filters
+}
+
+func graphCredentials(m map[string]interface{}) map[string]interface{} {
+	components, _ := m["components"].([]interface{})
+
+	for _, c := range components {
+		x, _ := c.(map[string]interface{})
+		if x["_component"] == "credentials" {
+			return x
 		}
 	}
-	return ""
+
+	return nil
+}
+
+func getGraphProvider(m map[string]interface{}) string {
+	provider, _ := graphCredentials(m)["_provider"].(string)
+	return provider
 }
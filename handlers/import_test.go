@@ -0,0 +1,103 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ernestio/definition-mapper/handlers"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/stretchr/testify/suite"
+)
+
+// ImportTestSuite : Test suite for imports
+type ImportTestSuite struct {
+	suite.Suite
+}
+
+const existingDefinition = `name: payments
+project: acme
+vpcs:
+  - name: main   # shared
+    subnet: 10.0.0.0/16
+`
+
+func vpc(name, subnet, id string) map[string]interface{} {
+	return map[string]interface{}{
+		"_component":    "vpc",
+		"_component_id": "vpc::" + name,
+		"_provider":     "aws",
+		"_state":        "completed",
+		"name":          name,
+		"subnet":        subnet,
+		"vpc_aws_id":    id,
+	}
+}
+
+// TestImportMerge : Testing imported components are merged into the
+// definition and mapping of the build set on the import request
+func (suite *ImportTestSuite) TestImportMerge() {
+	r := request.Request{
+		ID:          "build-2",
+		Name:        "acme/payments",
+		Credentials: map[string]interface{}{"type": "aws", "region": "eu-west-1"},
+		Merge:       existingDefinition,
+		From: map[string]interface{}{
+			"id":         "build-1",
+			"name":       "acme/payments",
+			"components": []interface{}{vpc("main", "10.0.0.0/16", "vpc-0001")},
+		},
+	}
+
+	g, err := handlers.Import(&r)
+	suite.Nil(err)
+
+	// the completed import is sent back with the components that were found
+	data, err := json.Marshal(g)
+	suite.Nil(err)
+
+	var ig map[string]interface{}
+	suite.Nil(json.Unmarshal(data, &ig))
+
+	var found []interface{}
+	for _, c := range ig["components"].([]interface{}) {
+		if c.(map[string]interface{})["_component"] == "credentials" {
+			found = append(found, c)
+		}
+	}
+
+	ig["components"] = append(found, vpc("main", "10.0.0.0/16", "vpc-0001"), vpc("legacy", "10.1.0.0/16", "vpc-0002"))
+
+	// only the id of the build merged into is sent, which must be loaded again
+	suite.NotContains(string(data), "vpc-0001")
+	suite.Equal("build-1", handlers.MergeBuild(ig))
+
+	_, err = handlers.ImportComplete(ig)
+	suite.NotNil(err)
+
+	handlers.SetMergeTarget(ig, existingDefinition, r.From)
+
+	b, err := handlers.ImportComplete(ig)
+	suite.Nil(err)
+	suite.Contains(b.Definition, "- name: main   # shared")
+	suite.Contains(b.Definition, "name: legacy")
+	suite.Len(b.Merge.Added, 1)
+	suite.Equal("build-2", b.Mapping.ID)
+	suite.NotNil(b.Mapping.Component("vpc::main"))
+	suite.NotNil(b.Mapping.Component("vpc::legacy"))
+
+	data, err = json.Marshal(b.Mapping)
+	suite.Nil(err)
+	suite.NotContains(string(data), handlers.MERGEBUILD)
+	suite.NotContains(string(data), handlers.MERGEDEFINITION)
+	suite.NotContains(string(data), handlers.IMPORTFILTERS)
+
+	// the current mapping must be sent along with the definition
+	r.From = nil
+	_, err = handlers.Import(&r)
+	suite.NotNil(err)
+}
+
+// TestImportTestSuite : Test suite for imports
+func TestImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportTestSuite))
+}
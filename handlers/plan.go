@@ -0,0 +1,69 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"github.com/ernestio/definition-mapper/cost"
+	"github.com/ernestio/definition-mapper/plan"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/r3labs/graph"
+)
+
+// Plan : handles a plan request, returning the changes needed to move from the
+// previous mapping to either the definition or the new mapping, along with
+// the change in estimated cost and any policy violations. If neither are set,
+// the removal of all components is planned
+func Plan(r *request.Request) (*plan.Plan, error) {
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
+	}
+
+	from := graph.New()
+	to := graph.New()
+
+	if r.From != nil {
+		from, err = r.FromMapping(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case r.Definition != nil:
+		to, err = r.DefinitionToGraph(m)
+		if err != nil {
+			return nil, err
+		}
+
+		// keep any values populated by the provider on the previous mapping
+		for _, c := range to.Components {
+			oc := from.Component(c.GetID())
+			if oc != nil {
+				c.Update(oc)
+			}
+		}
+	case r.To != nil:
+		to, err = r.ToMapping(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p, err := plan.New(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Cost = cost.Compare(from, to, r.PriceTable())
+
+	// policies are reported on the plan, but do not prevent it being returned
+	p.Policy, err = Policies(r, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
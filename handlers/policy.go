@@ -0,0 +1,73 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"strings"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers"
+	"github.com/ernestio/definition-mapper/policy"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/r3labs/graph"
+)
+
+// Policies : evaluates the rules set by POLICIES_FILE, along with those of the
+// request, against a mapped graph. Returns nil if there are no rules to evaluate
+func Policies(r *request.Request, g *graph.Graph) (*policy.Result, error) {
+	rules := append([]policy.Rule{}, policy.DefaultRules()...)
+	rules = append(rules, r.Policies...)
+	if len(rules) < 1 {
+		return nil, nil
+	}
+
+	ctx := policy.Context{}
+	ctx.Provider, _ = providers.Name(r.Provider())
+
+	parts := strings.SplitN(r.Name, "/", 2)
+	if len(parts) > 1 {
+		ctx.Project = parts[0]
+		ctx.Service = parts[1]
+	}
+
+	result, err := policy.Evaluate(g, rules, ctx)
+	if err != nil {
+		return nil, libmapper.NewError(libmapper.ErrCodeInvalidPolicy, err.Error())
+	}
+
+	return result, nil
+}
+
+// CheckPolicies : evaluates policies against a mapped graph, setting the result
+// on the request. Returns an error listing every violation if a rule with a
+// deny severity has been violated
+func CheckPolicies(r *request.Request, g *graph.Graph) (*policy.Result, error) {
+	result, err := Policies(r, g)
+	if err != nil {
+		return nil, err
+	}
+
+	r.PolicyResult = result
+
+	if result == nil || result.Passed {
+		return result, nil
+	}
+
+	errs := libmapper.Errors{}
+
+	for _, v := range result.Violations {
+		e := libmapper.NewError(libmapper.ErrCodePolicyViolation, "policy '"+v.Rule+"': "+v.Message)
+		e.ComponentID = v.ComponentID
+		e.ComponentType = v.ComponentType
+
+		if v.Severity == policy.WARN {
+			e.Severity = libmapper.SeverityWarning
+		}
+
+		errs = append(errs, e)
+	}
+
+	return result, errs
+}
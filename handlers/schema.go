@@ -0,0 +1,23 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers"
+)
+
+// Schema : handles a schema request, returning the json schema of a provider's definition format
+func Schema(provider string) (map[string]interface{}, error) {
+	m, err := providers.NewMapper(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	s := libmapper.DefinitionSchema(m)
+	s["title"] = provider + " definition"
+
+	return s, nil
+}
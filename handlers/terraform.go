@@ -0,0 +1,44 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/ernestio/definition-mapper/terraform"
+	"github.com/r3labs/graph"
+)
+
+// Terraform : handles a terraform request, exporting the previous mapping as
+// terraform configuration. If a definition is set, its mapping is exported instead
+func Terraform(r *request.Request) (*terraform.Export, error) {
+	name, _ := providers.Name(r.Provider())
+	if !terraform.Supported(name) {
+		return nil, libmapper.NewError(libmapper.ErrCodeUnsupportedOperation, "services of provider '"+r.Provider()+"' cannot be exported to terraform")
+	}
+
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
+	}
+
+	var g *graph.Graph
+
+	switch {
+	case r.Definition != nil:
+		g, err = r.DefinitionToGraph(m)
+	case r.From != nil:
+		g, err = r.FromMapping(m)
+	default:
+		return nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "terraform requests must set either a definition or a previous mapping (from)")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return terraform.New(g, name)
+}
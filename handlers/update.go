@@ -5,9 +5,7 @@
 package handlers
 
 import (
-	"errors"
-
-	"github.com/ernestio/definition-mapper/libmapper/providers"
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/ernestio/definition-mapper/request"
 	"github.com/r3labs/graph"
 )
@@ -16,11 +14,9 @@ import (
 func Update(r *request.Request) (*graph.Graph, error) {
 	var g *graph.Graph
 
-	p := r.Provider()
-
-	m := providers.NewMapper(p)
-	if m == nil {
-		return nil, errors.New("could not infer environment provider type")
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
 	}
 
 	dg, err := r.DefinitionToGraph(m)
@@ -51,6 +47,7 @@ func Update(r *request.Request) (*graph.Graph, error) {
 	}
 
 	creds := m.ProviderCredentials(r.Credentials)
+	libmapper.SetSecretReferences(creds, libmapper.GraphSecretReferences(dg))
 	g.UpdateComponent(creds)
 
 	for i := range g.Changes {
@@ -62,5 +59,11 @@ func Update(r *request.Request) (*graph.Graph, error) {
 	g.UserID = r.UserID
 	g.Username = r.Username
 
-	return g, err
+	// graphs violating a rule with a deny severity are never returned to be built
+	_, err = CheckPolicies(r, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
 }
@@ -0,0 +1,57 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package handlers
+
+import (
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/request"
+)
+
+// ValidationResult : the outcome of validating a definition
+type ValidationResult struct {
+	Valid    bool             `json:"valid"`
+	Errors   libmapper.Errors `json:"errors"`
+	Warnings libmapper.Errors `json:"warnings"`
+}
+
+// Validate : handles a validate request, returning every error found on the definition
+func Validate(r *request.Request) (*ValidationResult, error) {
+	v := ValidationResult{
+		Errors:   libmapper.Errors{},
+		Warnings: libmapper.Errors{},
+	}
+
+	m, err := r.Mapper()
+	if err != nil {
+		return nil, err
+	}
+
+	gd, errs := libmapper.ResolveDefinition(r.Definition, r.Variables, r.Modules)
+	v.Errors = append(v.Errors, errs...)
+
+	// unknown keys are only treated as errors in strict mode
+	unknown := libmapper.UnknownDefinitionKeys(m, gd)
+	if r.Strict {
+		v.Errors = append(v.Errors, unknown...)
+	} else {
+		v.Warnings = append(v.Warnings, libmapper.AsWarnings(unknown)...)
+	}
+
+	// the definition can't be reliably validated until all variables and modules resolve
+	if len(errs) > 0 {
+		v.Valid = false
+		return &v, nil
+	}
+
+	d, err := m.LoadDefinition(gd)
+	if err != nil {
+		v.Errors = append(v.Errors, libmapper.DecodeErrors(err)...)
+	}
+
+	v.Errors = append(v.Errors, libmapper.ValidateDefinition(m, d)...)
+	v.Valid = len(v.Errors) == 0
+
+	return &v, nil
+}
@@ -6,12 +6,26 @@ package main
 
 import (
 	"encoding/json"
-	"log"
+	"net/http"
+	"time"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/logger"
 )
 
-// Error : default error message
+// Error : default error message. _errors holds the typed errors
+// that make up _error, for clients that need more detail
 type Error struct {
-	Error string `json:"_error"`
+	Error  string           `json:"_error"`
+	Errors libmapper.Errors `json:"_errors"`
+}
+
+// errorMessage : returns the error message for a failed request
+func errorMessage(err error) Error {
+	return Error{
+		Error:  err.Error(),
+		Errors: libmapper.ToErrors(err, libmapper.ErrCodeInternal),
+	}
 }
 
 func response(reply string, data *[]byte, err *error) {
@@ -21,12 +35,58 @@ func response(reply string, data *[]byte, err *error) {
 	}
 
 	if *err != nil {
-		rdata, _ = json.Marshal(Error{Error: (*err).Error()})
+		rdata, _ = json.Marshal(errorMessage(*err))
 	}
 
 	if reply != "" {
 		_ = n.Publish(reply, rdata)
-	} else if *err != nil {
-		log.Println(*err)
 	}
 }
+
+// withValue : adds a value to an encoded graph under the given key, such as
+// the result of evaluating policies under _policy
+func withValue(data []byte, key string, value interface{}) ([]byte, error) {
+	var m map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	m[key], err = json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(m)
+}
+
+// logResult : writes the outcome of a handled message, along with the codes of any errors
+func logResult(l *logger.Logger, start time.Time, err *error) {
+	fields := logger.Fields{"duration": time.Since(start).Seconds()}
+
+	if *err == nil {
+		l.Info("request completed", fields)
+		return
+	}
+
+	var codes []string
+	for _, e := range libmapper.ToErrors(*err, libmapper.ErrCodeInternal) {
+		codes = append(codes, e.Code)
+	}
+
+	fields["codes"] = codes
+	fields["error"] = (*err).Error()
+
+	l.Error("request failed", fields)
+}
+
+func httpResponse(w http.ResponseWriter, status int, data []byte, err error) {
+	if err != nil {
+		data, _ = json.Marshal(errorMessage(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,135 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/handlers"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/nats-io/go-nats"
+)
+
+// StartHTTPServer : start an http listener exposing the mapping handlers
+func StartHTTPServer(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/mapping/", mappingEndpoint)
+	mux.HandleFunc("/v1/import/complete", importCompleteEndpoint)
+	mux.HandleFunc("/v1/schema/", schemaEndpoint)
+	mux.HandleFunc("/v1/capabilities", capabilitiesEndpoint)
+
+	listen(addr, mux)
+}
+
+// mappingEndpoint : handles requests made to /v1/mapping/:operation
+func mappingEndpoint(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		httpResponse(w, http.StatusMethodNotAllowed, nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "method not allowed"))
+		return
+	}
+
+	op := strings.TrimPrefix(req.URL.Path, "/v1/mapping/")
+
+	if mappingHandler(op) == nil {
+		httpResponse(w, http.StatusNotFound, nil, libmapper.NewError(libmapper.ErrCodeUnsupportedOperation, "unsupported mapping operation: "+op))
+		return
+	}
+
+	serveHTTP(w, req, "mapping.get."+op, mappingMessage)
+}
+
+// importCompleteEndpoint : converts a completed import graph to a build and stores it
+func importCompleteEndpoint(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		httpResponse(w, http.StatusMethodNotAllowed, nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "method not allowed"))
+		return
+	}
+
+	serveHTTP(w, req, "build.import.done", importDoneMessage)
+}
+
+// serveHTTP : handles the body of a request with the message handler of the
+// equivalent nats subject, on the same worker pool and request timeout.
+// Requests stop being handled once the client disconnects
+func serveHTTP(w http.ResponseWriter, req *http.Request, subject string, h messageHandler) {
+	defer req.Body.Close()
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		httpResponse(w, http.StatusBadRequest, nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, err.Error()))
+		return
+	}
+
+	results := make(chan result, 1)
+
+	dispatch(req.Context(), h, &nats.Msg{Subject: subject, Data: data}, func(r result) {
+		results <- r
+	})
+
+	r := <-results
+	if r.err != nil {
+		httpResponse(w, httpStatus(r.err), nil, r.err)
+		return
+	}
+
+	httpResponse(w, http.StatusOK, r.data, nil)
+}
+
+// httpStatus : returns the status code for a failed request, from the code of its first error
+func httpStatus(err error) int {
+	switch libmapper.ToErrors(err, libmapper.ErrCodeInternal)[0].Code {
+	case libmapper.ErrCodeTimeout:
+		return http.StatusGatewayTimeout
+	case libmapper.ErrCodeInternal:
+		return http.StatusInternalServerError
+	case libmapper.ErrCodeInvalidRequest:
+		return http.StatusBadRequest
+	case libmapper.ErrCodeUnsupportedOperation:
+		return http.StatusNotFound
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// schemaEndpoint : returns the definition json schema for /v1/schema/:provider
+func schemaEndpoint(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		httpResponse(w, http.StatusMethodNotAllowed, nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "method not allowed"))
+		return
+	}
+
+	s, err := handlers.Schema(strings.TrimPrefix(req.URL.Path, "/v1/schema/"))
+	if err != nil {
+		httpResponse(w, http.StatusNotFound, nil, err)
+		return
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		httpResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	httpResponse(w, http.StatusOK, data, nil)
+}
+
+// capabilitiesEndpoint : returns what the running mapper supports
+func capabilitiesEndpoint(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		httpResponse(w, http.StatusMethodNotAllowed, nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, "method not allowed"))
+		return
+	}
+
+	data, err := capabilitiesOperation(nil)
+	if err != nil {
+		httpResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	httpResponse(w, http.StatusOK, data, nil)
+}
@@ -0,0 +1,125 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers"
+	"github.com/ernestio/definition-mapper/metrics"
+	"github.com/r3labs/graph"
+)
+
+var (
+	requestsTotal   = metrics.NewCounter("mapper_requests_total", "Requests handled, by subject and provider.", "subject", "provider")
+	errorsTotal     = metrics.NewCounter("mapper_request_errors_total", "Requests that failed, by subject, provider and error code.", "subject", "provider", "code")
+	requestDuration = metrics.NewHistogram("mapper_request_duration_seconds", "Time taken to handle a request, by subject and provider.", metrics.DURATIONBUCKETS, "subject", "provider")
+	graphComponents = metrics.NewHistogram("mapper_graph_components", "Components of returned graphs, by operation and provider.", metrics.SIZEBUCKETS, "operation", "provider")
+	graphChanges    = metrics.NewHistogram("mapper_graph_changes", "Changes of returned graphs, by operation and provider.", metrics.SIZEBUCKETS, "operation", "provider")
+)
+
+// connectionStatus : implemented by connectors that can report if they are connected
+type connectionStatus interface {
+	IsConnected() bool
+}
+
+// observe : records a handled request
+func observe(subject, provider string, start time.Time, err error) {
+	subject = subjectLabel(subject)
+	provider = providerLabel(provider)
+
+	requestsTotal.WithLabelValues(subject, provider).Inc()
+	requestDuration.WithLabelValues(subject, provider).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		errorsTotal.WithLabelValues(subject, provider, libmapper.ToErrors(err, libmapper.ErrCodeInternal)[0].Code).Inc()
+	}
+}
+
+// observeGraph : records the size of a returned graph
+func observeGraph(operation, provider string, g *graph.Graph) {
+	operation = metrics.Label(operation, OPERATIONS...)
+	provider = providerLabel(provider)
+
+	graphComponents.WithLabelValues(operation, provider).Observe(float64(len(g.Components)))
+	graphChanges.WithLabelValues(operation, provider).Observe(float64(len(g.Changes)))
+}
+
+// subjectLabel : returns the subject a request is recorded under. Subjects
+// of unsupported operations or providers are recorded as unknown
+func subjectLabel(subject string) string {
+	parts := strings.Split(subject, ".")
+
+	switch {
+	case subject == "build.import.done":
+		return subject
+	case len(parts) == 3 && parts[0] == "mapping" && parts[1] == "get" && parts[2] == "capabilities":
+		return subject
+	case len(parts) == 3 && parts[0] == "mapping" && parts[1] == "get":
+		return "mapping.get." + metrics.Label(parts[2], OPERATIONS...)
+	case len(parts) == 4 && parts[0] == "mapping" && parts[1] == "get" && parts[2] == "schema":
+		return "mapping.get.schema." + providerLabel(parts[3])
+	}
+
+	return metrics.UNKNOWN
+}
+
+// providerLabel : returns the registered name of a provider, as requests can
+// name providers by any alias. Requests without a provider are recorded
+// without one, while unregistered providers are recorded as unknown
+func providerLabel(provider string) string {
+	if provider == "" {
+		return ""
+	}
+
+	name, ok := providers.Name(provider)
+	if !ok {
+		return metrics.UNKNOWN
+	}
+
+	return name
+}
+
+// natsConnected : returns true if the nats connection is established
+func natsConnected() bool {
+	if n == nil {
+		return false
+	}
+
+	c, ok := n.(connectionStatus)
+	if !ok {
+		return true
+	}
+
+	return c.IsConnected()
+}
+
+// StartMetricsServer : start an http listener exposing metrics and the health of the service
+func StartMetricsServer(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", healthEndpoint)
+
+	listen(addr, mux)
+}
+
+// healthEndpoint : reports the service as unavailable while nats is disconnected,
+// or once it has started shutting down
+func healthEndpoint(w http.ResponseWriter, req *http.Request) {
+	if isDraining() {
+		httpResponse(w, http.StatusServiceUnavailable, []byte(`{"status": "draining"}`), nil)
+		return
+	}
+
+	if !natsConnected() {
+		httpResponse(w, http.StatusServiceUnavailable, []byte(`{"status": "unavailable", "nats": "disconnected"}`), nil)
+		return
+	}
+
+	httpResponse(w, http.StatusOK, []byte(`{"status": "ok", "nats": "connected"}`), nil)
+}
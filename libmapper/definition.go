@@ -2,3 +2,10 @@ package libmapper
 
 // Definition interface
 type Definition interface{}
+
+// NamedDefinition : a definition whose service name and project can be set,
+// used when converting a graph back into a definition
+type NamedDefinition interface {
+	SetName(string)
+	SetProject(string)
+}
@@ -0,0 +1,174 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"strings"
+
+	"github.com/r3labs/graph"
+)
+
+const (
+	// SeverityError : the problem prevents the definition from being mapped
+	SeverityError = "error"
+	// SeverityWarning : the problem is reported, but does not prevent the definition from being mapped
+	SeverityWarning = "warning"
+
+	// ErrCodeRequired : a required field has not been set
+	ErrCodeRequired = "required"
+	// ErrCodeInvalidValue : a field is not set to one of its supported values
+	ErrCodeInvalidValue = "invalid_value"
+	// ErrCodeInvalidFormat : a field value is not in the expected format
+	ErrCodeInvalidFormat = "invalid_format"
+	// ErrCodeOutOfRange : a numeric field is outside of its supported range
+	ErrCodeOutOfRange = "out_of_range"
+	// ErrCodeInvalidType : a field could not be decoded to its expected type
+	ErrCodeInvalidType = "invalid_type"
+	// ErrCodeUnknownKey : a definition key does not match any definition field
+	ErrCodeUnknownKey = "unknown_key"
+	// ErrCodeUnresolvedDependency : a component references a component that does not exist
+	ErrCodeUnresolvedDependency = "unresolved_dependency"
+	// ErrCodeUnresolvedVariable : a definition references a variable that is not declared or has no value
+	ErrCodeUnresolvedVariable = "unresolved_variable"
+	// ErrCodeUnresolvedModule : a definition uses a module that could not be found
+	ErrCodeUnresolvedModule = "unresolved_module"
+	// ErrCodeUnresolvedSecret : a definition references a secret that could not be resolved
+	ErrCodeUnresolvedSecret = "unresolved_secret"
+	// ErrCodeInvalidComponent : a component failed validation
+	ErrCodeInvalidComponent = "invalid_component"
+	// ErrCodeInvalidDefinition : the definition could not be converted into a provider format
+	ErrCodeInvalidDefinition = "invalid_definition"
+	// ErrCodeUnsupportedProvider : the provider type of the request is not supported
+	ErrCodeUnsupportedProvider = "unsupported_provider"
+	// ErrCodeUnsupportedOperation : the requested operation is not supported
+	ErrCodeUnsupportedOperation = "unsupported_operation"
+	// ErrCodeInvalidRequest : the request could not be decoded
+	ErrCodeInvalidRequest = "invalid_request"
+	// ErrCodePolicyViolation : a component violates a policy rule
+	ErrCodePolicyViolation = "policy_violation"
+	// ErrCodeInvalidPolicy : a policy rule could not be compiled or evaluated
+	ErrCodeInvalidPolicy = "invalid_policy"
+	// ErrCodeAmbiguousGroup : imported components could not be grouped reliably
+	ErrCodeAmbiguousGroup = "ambiguous_group"
+	// ErrCodeTimeout : the request did not complete within the request timeout
+	ErrCodeTimeout = "timeout"
+	// ErrCodeInternal : an unexpected failure, not caused by the definition
+	ErrCodeInternal = "internal"
+)
+
+// Error : a typed error returned by component validation and handlers
+type Error struct {
+	Code          string `json:"code"`
+	Severity      string `json:"severity"`
+	ComponentID   string `json:"component_id,omitempty"`
+	ComponentType string `json:"component_type,omitempty"`
+	Field         string `json:"field,omitempty"`
+	Message       string `json:"message"`
+}
+
+// NewError : returns a new error with the given code
+func NewError(code, message string) Error {
+	return Error{Code: code, Severity: SeverityError, Message: message}
+}
+
+// NewFieldError : returns a new error with the given code for a specific field
+func NewFieldError(code, field, message string) Error {
+	return Error{Code: code, Severity: SeverityError, Field: field, Message: message}
+}
+
+// Error : returns the error as a string
+func (e Error) Error() string {
+	if e.ComponentID == "" {
+		return e.Message
+	}
+
+	return "Component '" + e.ComponentID + "': " + e.Message
+}
+
+// Errors : a collection of typed errors
+type Errors []Error
+
+// Error : returns all errors as a string
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Append : adds an error to the collection, untyped errors are reported with the given code
+func (e Errors) Append(err error, code string) Errors {
+	if err == nil {
+		return e
+	}
+
+	return append(e, ToErrors(err, code)...)
+}
+
+// Err : returns the collection as an error, or nil when it is empty
+func (e Errors) Err() error {
+	if len(e) < 1 {
+		return nil
+	}
+
+	return e
+}
+
+// AsWarnings : returns the errors with their severity set to warning
+func AsWarnings(errs Errors) Errors {
+	warnings := make(Errors, len(errs))
+
+	for i := range errs {
+		warnings[i] = errs[i]
+		warnings[i].Severity = SeverityWarning
+	}
+
+	return warnings
+}
+
+// ToErrors : converts any error to a collection of typed errors.
+// Untyped errors are reported with the given code
+func ToErrors(err error, code string) Errors {
+	switch e := err.(type) {
+	case Errors:
+		return e
+	case Error:
+		return Errors{e}
+	case *Error:
+		return Errors{*e}
+	}
+
+	return Errors{NewError(code, err.Error())}
+}
+
+// ComponentError : returns a typed error for a failure of the given component,
+// keeping the code and field of any typed error returned by the component
+func ComponentError(c graph.Component, err error) Error {
+	e, ok := err.(Error)
+	if !ok {
+		e = NewError(ErrCodeInvalidComponent, err.Error())
+	}
+
+	if e.Severity == "" {
+		e.Severity = SeverityError
+	}
+
+	e.ComponentID = c.GetID()
+	e.ComponentType = c.GetType()
+
+	return e
+}
+
+// DependencyError : returns a typed error for a component dependency that could not be resolved
+func DependencyError(c graph.Component, dep string) Error {
+	e := NewFieldError(ErrCodeUnresolvedDependency, FieldPath(c, dep), "Could not resolve component dependency '"+dep+"'")
+	e.ComponentID = c.GetID()
+	e.ComponentType = c.GetType()
+
+	return e
+}
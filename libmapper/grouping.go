@@ -0,0 +1,204 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/r3labs/graph"
+)
+
+var indexedName = regexp.MustCompile(`^(.+)-([0-9]+)$`)
+
+// Grouping : how components of a type that were not created by ernest are
+// grouped into count based definition entries on import
+type Grouping struct {
+	// Tag : the tag holding the name of a component's group
+	Tag string
+	// Signature : the properties that must be identical for components to be grouped
+	Signature func(graph.Component) string
+	// IP : the address of a component, expected to be contiguous within a group. Optional
+	IP func(graph.Component) string
+	// SetGroup : sets the group of a component
+	SetGroup func(graph.Component, string)
+}
+
+// groupMember : a component, along with the base name and index taken from its name
+type groupMember struct {
+	component graph.Component
+	base      string
+	index     int
+	indexed   bool
+}
+
+// GroupComponents : reconstructs the groups of components of a type that are
+// not tagged with a group, setting the group of each. Components named
+// '<name>-<n>' whose signatures are identical are grouped as '<name>'.
+// Groupings that may not be mapped back to the same components, such as
+// groups whose indexes or addresses are not contiguous, are returned as
+// warnings. Members of a group are ordered by index within the graph, so the
+// first member of a group holds its lowest index and address
+func GroupComponents(g *graph.Graph, ctype string, gr Grouping) Errors {
+	var order []string
+
+	warnings := Errors{}
+	buckets := make(map[string][]groupMember)
+	tagged := make(map[string]bool)
+
+	for _, c := range g.GetComponents().ByType(ctype) {
+		if group := c.GetTag(gr.Tag); group != "" {
+			tagged[group] = true
+			continue
+		}
+
+		m := groupMember{component: c, base: c.GetName()}
+
+		parts := indexedName.FindStringSubmatch(c.GetName())
+		if parts != nil {
+			m.base = parts[1]
+			m.index, _ = strconv.Atoi(parts[2])
+			m.indexed = true
+		}
+
+		if _, ok := buckets[m.base]; !ok {
+			order = append(order, m.base)
+		}
+
+		buckets[m.base] = append(buckets[m.base], m)
+	}
+
+	for _, base := range order {
+		members := buckets[base]
+
+		if tagged[base] || !sameSignature(members, gr) || !uniqueIndexes(members) {
+			if len(members) > 1 || tagged[base] {
+				warnings = append(warnings, groupWarning(members[0].component, fmt.Sprintf("Components named '%s-<n>' could not be grouped, as their properties differ or they clash with an existing group, so are imported separately", base)))
+			}
+
+			// components imported separately form groups of one, named after the component
+			for _, m := range members {
+				gr.SetGroup(m.component, m.component.GetName())
+				warnings = append(warnings, renameWarning(m.component, m.component.GetName()))
+			}
+
+			continue
+		}
+
+		sort.SliceStable(members, func(i, j int) bool {
+			return members[i].index < members[j].index
+		})
+
+		for _, m := range members {
+			gr.SetGroup(m.component, base)
+		}
+
+		if !contiguousIndexes(members) {
+			warnings = append(warnings, renameWarning(members[0].component, base))
+		}
+
+		if gr.IP != nil && !contiguousIPs(members, gr) {
+			warnings = append(warnings, groupWarning(members[0].component, fmt.Sprintf("Components of group '%s' do not have contiguous ip addresses, so will be assigned addresses from %s onwards when mapped", base, gr.IP(members[0].component))))
+		}
+
+		reorder(g, members)
+	}
+
+	return warnings
+}
+
+func sameSignature(members []groupMember, gr Grouping) bool {
+	for _, m := range members {
+		if !m.indexed || gr.Signature(m.component) != gr.Signature(members[0].component) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func uniqueIndexes(members []groupMember) bool {
+	seen := make(map[int]bool)
+
+	for _, m := range members {
+		if seen[m.index] {
+			return false
+		}
+		seen[m.index] = true
+	}
+
+	return true
+}
+
+// contiguousIndexes : returns true if members are indexed from 1 onwards, as
+// they are named when a count based entry is mapped
+func contiguousIndexes(members []groupMember) bool {
+	for i, m := range members {
+		if m.index != i+1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func contiguousIPs(members []groupMember, gr Grouping) bool {
+	first := net.ParseIP(gr.IP(members[0].component)).To4()
+	if first == nil {
+		return true
+	}
+
+	start := binary.BigEndian.Uint32(first)
+
+	for i, m := range members {
+		ip := net.ParseIP(gr.IP(m.component)).To4()
+		if ip == nil || binary.BigEndian.Uint32(ip) != start+uint32(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reorder : orders the members of a group by index, keeping the positions
+// they hold within the graph
+func reorder(g *graph.Graph, members []groupMember) {
+	var positions []int
+
+	ids := make(map[string]bool)
+	for _, m := range members {
+		ids[m.component.GetID()] = true
+	}
+
+	for i, c := range g.Components {
+		if ids[c.GetID()] {
+			positions = append(positions, i)
+		}
+	}
+
+	for i, pos := range positions {
+		if i < len(members) {
+			g.Components[pos] = members[i].component
+		}
+	}
+}
+
+func groupWarning(c graph.Component, message string) Error {
+	return Error{
+		Code:          ErrCodeAmbiguousGroup,
+		Severity:      SeverityWarning,
+		ComponentID:   c.GetID(),
+		ComponentType: c.GetType(),
+		Message:       message,
+	}
+}
+
+func renameWarning(c graph.Component, group string) Error {
+	return groupWarning(c, fmt.Sprintf("Components of group '%s' are not named '%s-1' onwards, so will be renamed when mapped", group, group))
+}
@@ -0,0 +1,91 @@
+package libmapper_test
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// GroupingTestSuite : Test suite for grouping imported components
+type GroupingTestSuite struct {
+	suite.Suite
+	Grouping libmapper.Grouping
+}
+
+func groupInstance(name, itype, ip string) *components.Instance {
+	return &components.Instance{
+		ComponentType: components.TYPEINSTANCE,
+		ComponentID:   components.TYPEINSTANCE + components.TYPEDELIMITER + name,
+		Name:          name,
+		Type:          itype,
+		IP:            ip,
+		Tags:          map[string]string{},
+	}
+}
+
+// SetupTest : Setup test suite
+func (suite *GroupingTestSuite) SetupTest() {
+	suite.Grouping = libmapper.Grouping{
+		Tag: components.GROUPINSTANCE,
+		Signature: func(c graph.Component) string {
+			return c.(*components.Instance).Type
+		},
+		IP: func(c graph.Component) string {
+			return c.(*components.Instance).IP
+		},
+		SetGroup: func(c graph.Component, group string) {
+			c.(*components.Instance).Tags[components.GROUPINSTANCE] = group
+		},
+	}
+}
+
+// TestGroupComponents : Testing groups are reconstructed from component names and properties
+func (suite *GroupingTestSuite) TestGroupComponents() {
+	tagged := groupInstance("app-1", "t2.micro", "10.0.1.1")
+	tagged.Tags[components.GROUPINSTANCE] = "app"
+
+	g := graph.New()
+	g.AddComponent(groupInstance("web-2", "t2.micro", "10.0.0.11"))
+	g.AddComponent(groupInstance("web-1", "t2.micro", "10.0.0.10"))
+	g.AddComponent(groupInstance("db-1", "t2.large", "10.0.0.20"))
+	g.AddComponent(groupInstance("db-2", "t2.micro", "10.0.0.21"))
+	g.AddComponent(tagged)
+	g.AddComponent(groupInstance("app-2", "t2.micro", "10.0.1.2"))
+
+	warnings := libmapper.GroupComponents(g, components.TYPEINSTANCE, suite.Grouping)
+
+	suite.Equal("web", g.Components[0].GetTag(components.GROUPINSTANCE))
+	suite.Equal("web", g.Components[1].GetTag(components.GROUPINSTANCE))
+	suite.Equal("web-1", g.Components[0].GetName())
+	suite.Equal("db-1", g.Components[2].GetTag(components.GROUPINSTANCE))
+	suite.Equal("db-2", g.Components[3].GetTag(components.GROUPINSTANCE))
+	suite.Equal("app", g.Components[4].GetTag(components.GROUPINSTANCE))
+	suite.Equal("app-2", g.Components[5].GetTag(components.GROUPINSTANCE))
+
+	suite.Len(warnings, 5)
+	for _, w := range warnings {
+		suite.Equal(libmapper.ErrCodeAmbiguousGroup, w.Code)
+		suite.Equal(libmapper.SeverityWarning, w.Severity)
+	}
+}
+
+// TestNonContiguousGroups : Testing groups whose indexes or addresses are not contiguous are reported
+func (suite *GroupingTestSuite) TestNonContiguousGroups() {
+	g := graph.New()
+	g.AddComponent(groupInstance("web-1", "t2.micro", "10.0.0.10"))
+	g.AddComponent(groupInstance("web-3", "t2.micro", "10.0.0.12"))
+
+	warnings := libmapper.GroupComponents(g, components.TYPEINSTANCE, suite.Grouping)
+	suite.Len(warnings, 2)
+	suite.Equal("web", g.Components[1].GetTag(components.GROUPINSTANCE))
+	suite.Contains(warnings[0].Message, "renamed")
+	suite.Contains(warnings[1].Message, "10.0.0.10")
+}
+
+// TestGroupingTestSuite : Test suite for grouping imported components
+func TestGroupingTestSuite(t *testing.T) {
+	suite.Run(t, new(GroupingTestSuite))
+}
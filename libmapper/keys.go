@@ -0,0 +1,156 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnknownKeys : walks a generic definition against the definition type,
+// returning an error for every key that does not match a definition field,
+// along with the closest known key as a suggestion
+func UnknownKeys(d Definition, i map[string]interface{}) Errors {
+	return unknownKeys(reflect.TypeOf(d), reflect.ValueOf(i), "")
+}
+
+func unknownKeys(t reflect.Type, v reflect.Value, path string) Errors {
+	errs := Errors{}
+
+	t = elem(t)
+
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for x := 0; x < v.Len(); x++ {
+			errs = append(errs, unknownKeys(t, v.Index(x), path+"["+strconv.Itoa(x)+"]")...)
+		}
+	case reflect.Map:
+		// maps are only checked if they are decoded onto a struct
+		if t.Kind() != reflect.Struct {
+			return errs
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(a, b int) bool {
+			return fmt.Sprint(keys[a].Interface()) < fmt.Sprint(keys[b].Interface())
+		})
+
+		for _, k := range keys {
+			key := fmt.Sprint(k.Interface())
+
+			f, ok := fieldByKey(t, key)
+			if !ok {
+				msg := "unknown key '" + key + "'"
+				if s := suggest(key, keysOf(t)); s != "" {
+					msg = msg + ", did you mean '" + s + "'?"
+				}
+
+				errs = append(errs, NewFieldError(ErrCodeUnknownKey, joinPath(path, key), msg))
+				continue
+			}
+
+			errs = append(errs, unknownKeys(f.Type, v.MapIndex(k), joinPath(path, key))...)
+		}
+	}
+
+	return errs
+}
+
+func elem(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if strings.EqualFold(jsonKey(t.Field(i)), key) {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func keysOf(t reflect.Type) []string {
+	var keys []string
+
+	if t.Kind() != reflect.Struct {
+		return keys
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if key := jsonKey(t.Field(i)); key != "-" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func jsonKey(f reflect.StructField) string {
+	key := strings.Split(f.Tag.Get("json"), ",")[0]
+	if key == "" {
+		return f.Name
+	}
+	return key
+}
+
+// suggest : returns the closest key to the one given, if any are similar enough
+func suggest(key string, keys []string) string {
+	var match string
+
+	best := len(key)/3 + 1
+
+	for _, k := range keys {
+		if d := distance(key, k); d <= best {
+			best = d
+			match = k
+		}
+	}
+
+	return match
+}
+
+// distance : returns the levenshtein distance between two strings
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minimum(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minimum(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,63 @@
+package libmapper_test
+
+// Basic imports
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	def "github.com/ernestio/definition-mapper/libmapper/providers/aws/definition"
+	"github.com/stretchr/testify/suite"
+)
+
+// KeysTestSuite : Test suite for unknown definition keys
+type KeysTestSuite struct {
+	suite.Suite
+	Definition map[string]interface{}
+}
+
+// SetupTest : Setup test suite
+func (suite *KeysTestSuite) SetupTest() {
+	suite.Definition = map[string]interface{}{
+		"name":    "test",
+		"project": "test",
+		"securty_groups": []interface{}{
+			map[string]interface{}{"name": "web"},
+		},
+		"instances": []interface{}{
+			map[string]interface{}{"name": "web", "elastic_ips": true},
+		},
+		"rds_instances": []interface{}{
+			map[string]interface{}{"name": "db", "storage": map[string]interface{}{"sise": 10}},
+		},
+		"iam_policies": []interface{}{
+			map[string]interface{}{"name": "policy", "policy_document": map[string]interface{}{"Version": "2012-10-17"}},
+		},
+	}
+}
+
+// TestUnknownKeys : Testing unknown keys are reported with a suggestion
+func (suite *KeysTestSuite) TestUnknownKeys() {
+	errs := libmapper.UnknownKeys(def.New(), suite.Definition)
+	suite.Equal(3, len(errs))
+
+	suite.Equal("instances[0].elastic_ips", errs[0].Field)
+	suite.Equal("unknown key 'elastic_ips', did you mean 'elastic_ip'?", errs[0].Message)
+
+	suite.Equal("rds_instances[0].storage.sise", errs[1].Field)
+	suite.Equal("unknown key 'sise', did you mean 'size'?", errs[1].Message)
+
+	suite.Equal("securty_groups", errs[2].Field)
+	suite.Equal("unknown key 'securty_groups', did you mean 'security_groups'?", errs[2].Message)
+}
+
+// TestUnknownKeysNoSuggestion : Testing unrelated keys are reported without a suggestion
+func (suite *KeysTestSuite) TestUnknownKeysNoSuggestion() {
+	errs := libmapper.UnknownKeys(def.New(), map[string]interface{}{"databases": []interface{}{}})
+	suite.Equal(1, len(errs))
+	suite.Equal("unknown key 'databases'", errs[0].Message)
+}
+
+// TestKeysTestSuite : tests for unknown definition keys
+func TestKeysTestSuite(t *testing.T) {
+	suite.Run(t, new(KeysTestSuite))
+}
@@ -1,6 +1,7 @@
 package libmapper
 
 import (
+	"github.com/ernestio/definition-mapper/logger"
 	"github.com/r3labs/graph"
 )
 
@@ -30,3 +31,109 @@ type Mapper interface {
 	// ProviderCredentials : Returns a provider specific mapped component
 	ProviderCredentials(map[string]interface{}) graph.Component
 }
+
+// DefinitionValidator : a mapper that can validate every component of a
+// definition, returning all errors found. Definitions of other mappers
+// return the error of converting them
+type DefinitionValidator interface {
+	ValidateDefinition(Definition) Errors
+}
+
+// DefinitionKeyChecker : a mapper that can report the keys of a generic
+// definition that don't match a definition field. Other mappers report none
+type DefinitionKeyChecker interface {
+	UnknownDefinitionKeys(map[string]interface{}) Errors
+}
+
+// SchemaMapper : a mapper that can describe its definition format as a json
+// schema. Other mappers are described as any object
+type SchemaMapper interface {
+	DefinitionSchema() map[string]interface{}
+}
+
+// ComponentTypeLister : a mapper that can list the component types it maps
+// definitions to. Other mappers list none
+type ComponentTypeLister interface {
+	ComponentTypes() []string
+}
+
+// LoggingMapper : a mapper that can write its log lines with a given logger,
+// so lines written while handling a request carry the request's fields
+type LoggingMapper interface {
+	SetLogger(*logger.Logger)
+}
+
+// ImportFilterValidator : a mapper that can validate the filters of an import
+// request before its import graph is created
+type ImportFilterValidator interface {
+	ValidateImportFilters([]string) Errors
+}
+
+// ImportFilterSelector : a mapper that chooses the filters an import is
+// queried with, from those set on the request and the name of the service.
+// Imports of other mappers are filtered by the name of the service
+type ImportFilterSelector interface {
+	ImportFilters(filters []string, service string) []string
+}
+
+// ImportFilteringMapper : a mapper that removes the components of a
+// completed import that do not match its filters, for filters a connector
+// may not apply on its queries
+type ImportFilteringMapper interface {
+	FilterImport(*graph.Graph, []string)
+}
+
+// GroupingMapper : a mapper that can reconstruct the groups of imported
+// components that were not created by ernest, before a graph is converted
+// to a definition. Ambiguous groupings are returned as warnings
+type GroupingMapper interface {
+	GroupComponents(*graph.Graph) Errors
+}
+
+// ValidateDefinition : returns every error found on a definition, or the
+// error of converting it for mappers that can't validate definitions
+func ValidateDefinition(m Mapper, d Definition) Errors {
+	if v, ok := m.(DefinitionValidator); ok {
+		return v.ValidateDefinition(d)
+	}
+
+	_, err := m.ConvertDefinition(d)
+	if err != nil {
+		return ToErrors(err, ErrCodeInvalidDefinition)
+	}
+
+	return nil
+}
+
+// UnknownDefinitionKeys : returns an error for every key of a generic
+// definition that doesn't match a definition field of the mapper
+func UnknownDefinitionKeys(m Mapper, gd map[string]interface{}) Errors {
+	if c, ok := m.(DefinitionKeyChecker); ok {
+		return c.UnknownDefinitionKeys(gd)
+	}
+
+	return nil
+}
+
+// DefinitionSchema : returns the json schema of a mapper's definition format
+func DefinitionSchema(m Mapper) map[string]interface{} {
+	if sm, ok := m.(SchemaMapper); ok {
+		return sm.DefinitionSchema()
+	}
+
+	return map[string]interface{}{
+		"$schema":    SCHEMAVERSION,
+		"type":       "object",
+		"required":   []string{"name", "project"},
+		"properties": map[string]interface{}{},
+	}
+}
+
+// ComponentTypes : returns the component types a mapper maps definitions to
+func ComponentTypes(m Mapper) []string {
+	if l, ok := m.(ComponentTypeLister); ok {
+		return l.ComponentTypes()
+	}
+
+	return []string{}
+}
@@ -0,0 +1,312 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MODULESKEY : the definition key modules are used on
+const MODULESKEY = "modules"
+
+// MODULEDELIMITER : separates the module name from the name of the components it declares
+const MODULEDELIMITER = "-"
+
+// MODULEREFERENCES : the definition keys whose values reference other
+// components by name, which are qualified along with the names they reference
+var MODULEREFERENCES = []string{
+	"availability_set", "backend_address_pool", "backend_address_pools", "cluster", "destination",
+	"iam_profile", "instances", "load_balancer_backend_address_pools", "loadbalancers", "nat_gateway",
+	"network", "networks", "policies", "probe", "rds_clusters", "rds_instances", "replication_source",
+	"roles", "security_group", "security_groups", "source", "storage_account", "storage_container",
+	"subnet", "volume", "vpc",
+}
+
+// ModuleUse : a module used by a definition
+type ModuleUse struct {
+	Name       string
+	Source     string
+	Parameters map[string]interface{}
+}
+
+// ResolveDefinition : resolves all variables of a generic definition,
+// then expands any modules it uses from the given module sources
+func ResolveDefinition(d map[string]interface{}, variables map[string]interface{}, sources map[string]interface{}) (map[string]interface{}, Errors) {
+	rd, errs := ResolveVariables(d, variables)
+	if len(errs) > 0 {
+		return rd, errs
+	}
+
+	return ExpandModules(rd, sources, nil)
+}
+
+// ExpandModules : expands the modules used by a generic definition into the
+// definition. Components declared by a module are prefixed with the name it
+// is used as, so they stay unique and keep the same id across updates
+func ExpandModules(d map[string]interface{}, sources map[string]interface{}, stack []string) (map[string]interface{}, Errors) {
+	errs := Errors{}
+
+	uses, uerrs := moduleUses(d[MODULESKEY])
+	errs = append(errs, uerrs...)
+
+	expanded := make(map[string]interface{}, len(d))
+	for k, v := range d {
+		if k != MODULESKEY {
+			expanded[k] = v
+		}
+	}
+
+	for i, u := range uses {
+		field := MODULESKEY + "[" + strconv.Itoa(i) + "]"
+
+		if isOneOf(stack, u.Source) {
+			errs = append(errs, NewFieldError(ErrCodeInvalidValue, field+".source", "module '"+u.Source+"' can not use itself"))
+			continue
+		}
+
+		source, ok := stringMap(sources[u.Source])
+		if !ok {
+			errs = append(errs, NewFieldError(ErrCodeUnresolvedModule, field+".source", "module '"+u.Source+"' could not be found"))
+			continue
+		}
+
+		// module parameters are the module's variables
+		md, merrs := ResolveVariables(source, u.Parameters)
+		if len(merrs) == 0 {
+			md, merrs = ExpandModules(md, sources, append(stack, u.Source))
+		}
+
+		if len(merrs) > 0 {
+			for _, e := range merrs {
+				if e.Field != "" {
+					e.Field = field + "." + e.Field
+				} else {
+					e.Field = field
+				}
+				e.Message = "module '" + u.Name + "': " + e.Message
+				errs = append(errs, e)
+			}
+			continue
+		}
+
+		md = qualifyNames(md, u.Name)
+
+		if cerrs := nameCollisions(expanded, md, field, u.Name); len(cerrs) > 0 {
+			errs = append(errs, cerrs...)
+			continue
+		}
+
+		for _, k := range sortedKeys(md) {
+			components, ok := md[k].([]interface{})
+			if !ok {
+				continue
+			}
+
+			existing, _ := expanded[k].([]interface{})
+			expanded[k] = append(existing, components...)
+		}
+	}
+
+	return expanded, errs
+}
+
+func moduleUses(i interface{}) ([]ModuleUse, Errors) {
+	var uses []ModuleUse
+
+	errs := Errors{}
+
+	if i == nil {
+		return uses, errs
+	}
+
+	l, ok := i.([]interface{})
+	if !ok {
+		return uses, Errors{NewFieldError(ErrCodeInvalidType, MODULESKEY, "modules must be a list")}
+	}
+
+	names := make(map[string]bool)
+
+	for x := range l {
+		field := MODULESKEY + "[" + strconv.Itoa(x) + "]"
+
+		m, ok := stringMap(l[x])
+		if !ok {
+			errs = append(errs, NewFieldError(ErrCodeInvalidType, field, "module must be a map"))
+			continue
+		}
+
+		var u ModuleUse
+
+		u.Name, _ = m["name"].(string)
+		u.Source, _ = m["source"].(string)
+		u.Parameters, _ = stringMap(m["parameters"])
+
+		if u.Name == "" {
+			errs = append(errs, NewFieldError(ErrCodeRequired, field+".name", "module name should not be null"))
+			continue
+		}
+
+		if u.Source == "" {
+			errs = append(errs, NewFieldError(ErrCodeRequired, field+".source", "module source should not be null"))
+			continue
+		}
+
+		if names[u.Name] {
+			errs = append(errs, NewFieldError(ErrCodeInvalidValue, field+".name", "module name '"+u.Name+"' is used more than once"))
+			continue
+		}
+
+		names[u.Name] = true
+		uses = append(uses, u)
+	}
+
+	return uses, errs
+}
+
+// qualifyNames : prefixes the names of the components declared by a module,
+// along with the values of reference fields naming them. The names of
+// entries nested under other entries, such as the virtual networks of an
+// azure resource group, are qualified as well, while tags and other values
+// are left as they are
+func qualifyNames(d map[string]interface{}, module string) map[string]interface{} {
+	names := make(map[string]bool)
+
+	for _, k := range sortedKeys(d) {
+		collectNames(d[k], names)
+	}
+
+	q := make(map[string]interface{}, len(d))
+
+	for k, v := range d {
+		l, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+
+		q[k] = qualifyEntries(l, k, module, names)
+	}
+
+	return q
+}
+
+// entryNames : returns the names of the entries of a top level list
+func entryNames(v interface{}) []string {
+	var names []string
+
+	l, _ := v.([]interface{})
+
+	for i := range l {
+		m, _ := stringMap(l[i])
+		if name, ok := m["name"].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// collectNames : adds the names of the entries of a list, and of the lists
+// nested under them, to names
+func collectNames(v interface{}, names map[string]bool) {
+	l, _ := v.([]interface{})
+
+	for i := range l {
+		m, ok := stringMap(l[i])
+		if !ok {
+			continue
+		}
+
+		if name, ok := m["name"].(string); ok && name != "" {
+			names[name] = true
+		}
+
+		for _, k := range sortedKeys(m) {
+			collectNames(m[k], names)
+		}
+	}
+}
+
+// qualifyEntries : qualifies the names of the entries of a list, along with
+// the references and nested entries they hold
+func qualifyEntries(l []interface{}, key, module string, names map[string]bool) []interface{} {
+	entries := make([]interface{}, len(l))
+
+	for i := range l {
+		m, ok := stringMap(l[i])
+		if !ok {
+			entries[i] = qualifyValue(l[i], key, module, names)
+			continue
+		}
+
+		e := qualifyMap(m, module, names)
+		if name, ok := m["name"].(string); ok && name != "" {
+			e["name"] = module + MODULEDELIMITER + name
+		}
+
+		entries[i] = e
+	}
+
+	return entries
+}
+
+func qualifyMap(m map[string]interface{}, module string, names map[string]bool) map[string]interface{} {
+	q := make(map[string]interface{}, len(m))
+
+	for k := range m {
+		q[k] = qualifyValue(m[k], k, module, names)
+	}
+
+	return q
+}
+
+func qualifyValue(v interface{}, key, module string, names map[string]bool) interface{} {
+	switch x := v.(type) {
+	case string:
+		if isOneOf(MODULEREFERENCES, key) {
+			return qualifyReference(x, module, names)
+		}
+	case []interface{}:
+		return qualifyEntries(x, key, module, names)
+	case map[string]interface{}, map[interface{}]interface{}:
+		m, _ := stringMap(x)
+		return qualifyMap(m, module, names)
+	}
+
+	return v
+}
+
+// qualifyReference : qualifies a reference to a component declared by the
+// module. References to nested components, such as an azure subnet's
+// 'network:subnet', have each of their names qualified
+func qualifyReference(ref, module string, names map[string]bool) string {
+	parts := strings.Split(ref, ":")
+
+	for i := range parts {
+		if names[parts[i]] {
+			parts[i] = module + MODULEDELIMITER + parts[i]
+		}
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// nameCollisions : returns an error for each component of an expanded
+// module whose qualified name is already declared by the definition
+func nameCollisions(d, md map[string]interface{}, field, module string) Errors {
+	errs := Errors{}
+
+	for _, k := range sortedKeys(md) {
+		declared := entryNames(d[k])
+
+		for _, name := range entryNames(md[k]) {
+			if isOneOf(declared, name) {
+				errs = append(errs, NewFieldError(ErrCodeInvalidValue, field+".name", "module '"+module+"' declares "+k+" '"+name+"', which is already declared"))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,114 @@
+package libmapper_test
+
+// Basic imports
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/stretchr/testify/suite"
+)
+
+// ModulesTestSuite : Test suite for definition modules
+type ModulesTestSuite struct {
+	suite.Suite
+	Definition map[string]interface{}
+	Sources    map[string]interface{}
+}
+
+// SetupTest : Setup test suite
+func (suite *ModulesTestSuite) SetupTest() {
+	suite.Definition = map[string]interface{}{
+		"name":    "test",
+		"project": "test",
+		"modules": []interface{}{
+			map[string]interface{}{
+				"name":       "net",
+				"source":     "baseline",
+				"parameters": map[string]interface{}{"cidr": "10.0.0.0/16"},
+			},
+		},
+		"instances": []interface{}{
+			map[string]interface{}{"name": "web", "network": "net-public"},
+		},
+	}
+
+	suite.Sources = map[string]interface{}{
+		"baseline": map[string]interface{}{
+			"variables": map[string]interface{}{
+				"cidr": map[string]interface{}{"required": true},
+			},
+			"vpcs": []interface{}{
+				map[string]interface{}{"name": "main", "subnet": "${var.cidr}"},
+			},
+			"networks": []interface{}{
+				map[string]interface{}{"name": "public", "vpc": "main", "subnet": "10.0.1.0/24"},
+			},
+			"instances": []interface{}{
+				map[string]interface{}{
+					"name":    "bastion",
+					"network": "public",
+					"image":   "main",
+					"volumes": []interface{}{map[string]interface{}{"volume": "main", "device": "/dev/sdf"}},
+				},
+			},
+		},
+	}
+}
+
+// TestExpandModules : Testing modules are expanded with qualified names
+func (suite *ModulesTestSuite) TestExpandModules() {
+	d, errs := libmapper.ResolveDefinition(suite.Definition, nil, suite.Sources)
+	suite.Equal(0, len(errs))
+	suite.Nil(d["modules"])
+
+	vpc := d["vpcs"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("net-main", vpc["name"])
+	suite.Equal("10.0.0.0/16", vpc["subnet"])
+
+	network := d["networks"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("net-public", network["name"])
+	suite.Equal("net-main", network["vpc"])
+
+	instances := d["instances"].([]interface{})
+	suite.Equal(2, len(instances))
+
+	// only reference fields are qualified
+	bastion := instances[1].(map[string]interface{})
+	suite.Equal("net-bastion", bastion["name"])
+	suite.Equal("net-public", bastion["network"])
+	suite.Equal("main", bastion["image"])
+	suite.Equal("net-main", bastion["volumes"].([]interface{})[0].(map[string]interface{})["volume"])
+}
+
+// TestModuleNameCollisions : Testing module components can not replace those of the definition
+func (suite *ModulesTestSuite) TestModuleNameCollisions() {
+	suite.Definition["vpcs"] = []interface{}{
+		map[string]interface{}{"name": "net-main", "subnet": "10.1.0.0/16"},
+	}
+
+	_, errs := libmapper.ResolveDefinition(suite.Definition, nil, suite.Sources)
+	suite.Equal(1, len(errs))
+	suite.Equal(libmapper.ErrCodeInvalidValue, errs[0].Code)
+	suite.Equal("modules[0].name", errs[0].Field)
+}
+
+// TestModuleErrors : Testing missing modules and parameters are reported
+func (suite *ModulesTestSuite) TestModuleErrors() {
+	use := suite.Definition["modules"].([]interface{})[0].(map[string]interface{})
+	delete(use, "parameters")
+
+	_, errs := libmapper.ResolveDefinition(suite.Definition, nil, suite.Sources)
+	suite.Equal(1, len(errs))
+	suite.Equal(libmapper.ErrCodeRequired, errs[0].Code)
+	suite.Equal("modules[0].variables.cidr", errs[0].Field)
+
+	_, errs = libmapper.ResolveDefinition(suite.Definition, nil, nil)
+	suite.Equal(1, len(errs))
+	suite.Equal(libmapper.ErrCodeUnresolvedModule, errs[0].Code)
+	suite.Equal("modules[0].source", errs[0].Field)
+}
+
+// TestModulesTestSuite : tests for definition modules
+func TestModulesTestSuite(t *testing.T) {
+	suite.Run(t, new(ModulesTestSuite))
+}
@@ -5,8 +5,7 @@
 package components
 
 import (
-	"errors"
-
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -31,7 +30,7 @@ type EBSVolume struct {
 	DatacenterName   string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID      string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service          string            `json:"service" diff:"-"`
 }
 
@@ -132,28 +131,28 @@ func (e *EBSVolume) SequentialDependencies() []string {
 // Validate : validates the components values
 func (e *EBSVolume) Validate() error {
 	if e.Name == "" {
-		return errors.New(EBSErrNilName)
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", EBSErrNilName)
 	}
 
 	if e.AvailabilityZone == "" {
-		return errors.New(EBSErrAvailabilityNameNil)
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "availability_zone", EBSErrAvailabilityNameNil)
 	}
 
 	if e.VolumeType == "" {
-		return errors.New(EBSErrNilType)
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "volume_type", EBSErrNilType)
 	}
 
 	if e.Encrypted && e.EncryptionKeyID == nil {
-		return errors.New(EBSErrNilEncryption)
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "encryption_key_id", EBSErrNilEncryption)
 	}
 
 	if e.VolumeType != "io1" && e.Iops != nil {
-		return errors.New(EBSErrInvalidType)
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "volume_type", EBSErrInvalidType)
 	}
 
 	if e.Size != nil {
 		if *e.Size < 1 || *e.Size > 16384 {
-			return errors.New(EBSErrInvalidSize)
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "size", EBSErrInvalidSize)
 		}
 	}
 
@@ -5,14 +5,18 @@
 package components
 
 import (
-	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
 
+// ELBPROTOCOLS : elb supported listener protocols
+var ELBPROTOCOLS = []string{"HTTP", "HTTPS", "TCP", "SSL"}
+
 // ELBListener ...
 type ELBListener struct {
 	FromPort int    `json:"from_port" diff:"from_port"`
@@ -44,7 +48,7 @@ type ELB struct {
 	DatacenterName      string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion    string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID         string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey     string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey     string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service             string            `json:"service" diff:"-"`
 }
 
@@ -213,15 +217,15 @@ func (e *ELB) SequentialDependencies() []string {
 // Validate : validates the components values
 func (e *ELB) Validate() error {
 	if e.Name == "" {
-		return errors.New("ELB name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "ELB name should not be null")
 	}
 
 	if len(e.Listeners) < 1 {
-		return errors.New("ELB must contain more than one listeners")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "listeners", "ELB must contain more than one listeners")
 	}
 
 	if e.IsPrivate != true && len(e.Networks) < 1 {
-		return errors.New("ELB must specify at least one subnet if public")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "networks", "ELB must specify at least one subnet if public")
 	}
 
 	/*
@@ -230,24 +234,21 @@ func (e *ELB) Validate() error {
 		}
 	*/
 
-	for _, listener := range e.Listeners {
+	for i, listener := range e.Listeners {
 		if listener.FromPort < 1 || listener.FromPort > 65535 {
-			return fmt.Errorf("From Port (%d) is out of range [1 - 65535]", listener.FromPort)
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "listeners["+strconv.Itoa(i)+"].from_port", fmt.Sprintf("From Port (%d) is out of range [1 - 65535]", listener.FromPort))
 		}
 
 		if listener.ToPort < 1 || listener.ToPort > 65535 {
-			return fmt.Errorf("From Port (%d) is out of range [1 - 65535]", listener.ToPort)
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "listeners["+strconv.Itoa(i)+"].to_port", fmt.Sprintf("To Port (%d) is out of range [1 - 65535]", listener.ToPort))
 		}
 
-		if listener.Protocol != "HTTP" &&
-			listener.Protocol != "HTTPS" &&
-			listener.Protocol != "TCP" &&
-			listener.Protocol != "SSL" {
-			return errors.New("ELB Protocol must be one of http, https, tcp or ssl")
+		if isOneOf(ELBPROTOCOLS, listener.Protocol) != true {
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "listeners["+strconv.Itoa(i)+"].protocol", "ELB Protocol must be one of http, https, tcp or ssl")
 		}
 
 		if listener.Protocol == "https" && listener.SSLCert == "" || listener.Protocol == "ssl" && listener.SSLCert == "" {
-			return errors.New("ELB listener must specify an ssl cert when protocol is https/ssl")
+			return libmapper.NewFieldError(libmapper.ErrCodeRequired, "listeners["+strconv.Itoa(i)+"].ssl_cert", "ELB listener must specify an ssl cert when protocol is https/ssl")
 		}
 
 	}
@@ -5,9 +5,9 @@
 package components
 
 import (
-	"errors"
 	"strings"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -28,7 +28,7 @@ type IamInstanceProfile struct {
 	DatacenterName          string   `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion        string   `json:"datacenter_region" diff:"-"`
 	AccessKeyID             string   `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey         string   `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey         string   `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Remove                  bool     `json:"-" diff:"-"`
 	Service                 string   `json:"service" diff:"-"`
 }
@@ -137,11 +137,11 @@ func (i *IamInstanceProfile) SequentialDependencies() []string {
 // Validate : validates the components values
 func (i *IamInstanceProfile) Validate() error {
 	if i.Name == "" {
-		return errors.New("iam instance profile name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "iam instance profile name should not be null")
 	}
 
 	if len(i.Roles) < 1 {
-		return errors.New("iam instance profile should specify at least one role")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "roles", "iam instance profile should specify at least one role")
 	}
 
 	return nil
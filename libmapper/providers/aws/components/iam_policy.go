@@ -5,9 +5,9 @@
 package components
 
 import (
-	"errors"
 	"strings"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -29,7 +29,7 @@ type IamPolicy struct {
 	DatacenterName   string `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion string `json:"datacenter_region" diff:"-"`
 	AccessKeyID      string `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey  string `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey  string `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Remove           bool   `json:"-" diff:"-"`
 	Service          string `json:"service" diff:"-"`
 }
@@ -141,11 +141,11 @@ func (i *IamPolicy) SequentialDependencies() []string {
 // Validate : validates the components values
 func (i *IamPolicy) Validate() error {
 	if i.Name == "" {
-		return errors.New("iam policy name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "iam policy name should not be null")
 	}
 
 	if i.PolicyDocument == "" {
-		return errors.New("iam policy document should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "policy_document", "iam policy document should not be null")
 	}
 
 	return nil
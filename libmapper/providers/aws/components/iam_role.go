@@ -5,9 +5,9 @@
 package components
 
 import (
-	"errors"
 	"strings"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -31,7 +31,7 @@ type IamRole struct {
 	DatacenterName       string   `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion     string   `json:"datacenter_region" diff:"-"`
 	AccessKeyID          string   `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey      string   `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey      string   `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Remove               bool     `json:"-" diff:"-"`
 	Service              string   `json:"service" diff:"-"`
 }
@@ -153,11 +153,11 @@ func (i *IamRole) SequentialDependencies() []string {
 // Validate : validates the components values
 func (i *IamRole) Validate() error {
 	if i.Name == "" {
-		return errors.New("iam role name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "iam role name should not be null")
 	}
 
 	if i.AssumePolicyDocument == "" {
-		return errors.New("iam role must specify a policy document")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "assume_policy_document", "iam role must specify a policy document")
 	}
 
 	return nil
@@ -5,8 +5,7 @@
 package components
 
 import (
-	"errors"
-
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -50,7 +49,7 @@ type Instance struct {
 	DatacenterName        string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion      string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID           string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey       string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey       string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service               string            `json:"service" diff:"-"`
 }
 
@@ -224,23 +223,23 @@ func (i *Instance) SequentialDependencies() []string {
 // Validate : validates the components values
 func (i *Instance) Validate() error {
 	if i.Name == "" {
-		return errors.New("Instance name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "Instance name should not be null")
 	}
 
 	if i.Type == "" {
-		return errors.New("Instance type should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "instance_type", "Instance type should not be null")
 	}
 
 	if i.Image == "" {
-		return errors.New("Instance image should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "image", "Instance image should not be null")
 	}
 
 	if i.Network == "" {
-		return errors.New("Instance network should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "network_name", "Instance network should not be null")
 	}
 
 	if len(i.SecurityGroups) != len(i.SecurityGroupAWSIDs) {
-		return errors.New("Instance security groups are incorrect")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "security_groups", "Instance security groups are incorrect")
 	}
 
 	return nil
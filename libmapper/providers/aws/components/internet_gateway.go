@@ -23,7 +23,7 @@ type InternetGateway struct {
 	DatacenterName       string            `json:"datacenter_name" diff:"-"`
 	DatacenterRegion     string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID          string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey      string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey      string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Vpc                  string            `json:"vpc" diff:"-"`
 	VpcID                string            `json:"vpc_id" diff:"-"`
 	Service              string            `json:"service" diff:"-"`
@@ -5,8 +5,7 @@
 package components
 
 import (
-	"errors"
-
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -31,7 +30,7 @@ type NatGateway struct {
 	DatacenterName         string            `json:"datacenter_name" diff:"-"`
 	DatacenterRegion       string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID            string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey        string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey        string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	VpcID                  string            `json:"vpc_id" diff:"-"`
 	Remove                 bool              `json:"-" diff:"-"`
 	Tags                   map[string]string `json:"tags" diff:"-"`
@@ -184,11 +183,11 @@ func (n *NatGateway) SequentialDependencies() []string {
 // Validate : validates the components values
 func (n *NatGateway) Validate() error {
 	if n.Name == "" {
-		return errors.New("Nat Gateway name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "Nat Gateway name should not be null")
 	}
 
 	if n.PublicNetwork == "" {
-		return errors.New("Nat Gateway should specify a public network")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "public_network", "Nat Gateway should specify a public network")
 	}
 
 	return nil
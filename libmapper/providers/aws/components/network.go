@@ -5,9 +5,9 @@
 package components
 
 import (
-	"errors"
 	"net"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -31,7 +31,7 @@ type Network struct {
 	DatacenterName       string            `json:"datacenter_name" diff:"-"`
 	DatacenterRegion     string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID          string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey      string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey      string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Vpc                  string            `json:"vpc" diff:"-"`
 	VpcID                string            `json:"vpc_id" diff:"-"`
 	Service              string            `json:"service" diff:"-"`
@@ -155,19 +155,19 @@ func (n *Network) SequentialDependencies() []string {
 func (n *Network) Validate() error {
 	_, _, err := net.ParseCIDR(n.Subnet)
 	if err != nil {
-		return errors.New("Network CIDR is not valid")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "subnet", "Network CIDR is not valid")
 	}
 
 	if n.Name == "" {
-		return errors.New("Network name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "Network name should not be null")
 	}
 
 	if n.Vpc == "" {
-		return errors.New("Network must specify a vpc")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "vpc", "Network must specify a vpc")
 	}
 
 	if n.IsPublic && n.Tags["ernest.nat_gateway"] != "" {
-		return errors.New("Public Network should not specify a nat gateway")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "nat_gateway", "Public Network should not specify a nat gateway")
 	}
 
 	return nil
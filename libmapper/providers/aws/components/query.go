@@ -17,11 +17,12 @@ type Query struct {
 	State            string            `json:"_state" diff:"-"`
 	Action           string            `json:"_action" diff:"-"`
 	Tags             map[string]string `json:"tags" diff:"-"`
+	VpcID            string            `json:"vpc_id,omitempty" diff:"-"`
 	DatacenterType   string            `json:"datacenter_type,omitempty" diff:"-"`
 	DatacenterName   string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID      string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service          string            `json:"service" diff:"-"`
 }
 
@@ -5,11 +5,11 @@
 package components
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"unicode"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -34,7 +34,7 @@ type RDSCluster struct {
 	NetworkAWSIDs       []string          `json:"network_aws_ids" diff:"-"`
 	DatabaseName        string            `json:"database_name,omitempty" diff:"database_name,immutable"`
 	DatabaseUsername    string            `json:"database_username,omitempty" diff:"database_username,immutable"`
-	DatabasePassword    string            `json:"database_password,omitempty" diff:"database_password"`
+	DatabasePassword    string            `json:"database_password,omitempty" diff:"database_password" sensitive:"true"`
 	BackupRetention     *int64            `json:"backup_retention,omitempty" diff:"backup_retention"`
 	BackupWindow        string            `json:"backup_window,omitempty" diff:"backup_window"`
 	MaintenanceWindow   string            `json:"maintenance_window,omitempty" diff:"maintenance_window"`
@@ -45,7 +45,7 @@ type RDSCluster struct {
 	DatacenterName      string            `json:"datacenter_name" diff:"-"`
 	DatacenterRegion    string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID         string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey     string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey     string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service             string            `json:"service" diff:"-"`
 }
 
@@ -188,66 +188,66 @@ func (r *RDSCluster) SequentialDependencies() []string {
 // Validate : validates the components values
 func (r *RDSCluster) Validate() error {
 	if r.Name == "" {
-		return errors.New("RDS Cluster name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "RDS Cluster name should not be null")
 	}
 
 	if len(r.Name) > 255 {
-		return errors.New("RDS Cluster name should not exceed 255 characters")
+		return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "name", "RDS Cluster name should not exceed 255 characters")
 	}
 
 	if r.Engine == "" {
-		return errors.New("RDS Cluster engine type should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "engine", "RDS Cluster engine type should not be null")
 	}
 
 	if r.ReplicationSource != "" {
 		if len(r.ReplicationSource) < 12 || r.ReplicationSource[:12] != "arn:aws:rds:" {
-			return errors.New("RDS Cluster replication source should be a valid amazon resource name (ARN), i.e. 'arn:aws:rds:us-east-1:123456789012:cluster:my-aurora-cluster'")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "replication_source", "RDS Cluster replication source should be a valid amazon resource name (ARN), i.e. 'arn:aws:rds:us-east-1:123456789012:cluster:my-aurora-cluster'")
 		}
 	}
 
 	if r.DatabaseName == "" {
-		return errors.New("RDS Cluster database name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "database_name", "RDS Cluster database name should not be null")
 	}
 
 	if len(r.DatabaseName) > 64 {
-		return errors.New("RDS Cluster database name should not exceed 64 characters")
+		return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "database_name", "RDS Cluster database name should not exceed 64 characters")
 	}
 
 	for _, c := range r.DatabaseName {
 		if unicode.IsLetter(c) != true && unicode.IsNumber(c) != true {
-			return errors.New("RDS Cluster database name can only contain alphanumeric characters")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "database_name", "RDS Cluster database name can only contain alphanumeric characters")
 		}
 	}
 
 	if r.DatabaseUsername == "" {
-		return errors.New("RDS Cluster database username should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "database_username", "RDS Cluster database username should not be null")
 	}
 
 	if len(r.DatabaseUsername) > 16 {
-		return errors.New("RDS Cluster database username should not exceed 16 characters")
+		return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "database_username", "RDS Cluster database username should not exceed 16 characters")
 	}
 
 	if r.DatabasePassword != "" {
 		if len(r.DatabasePassword) < 8 || len(r.DatabasePassword) > 41 {
-			return errors.New("RDS Cluster database password should be between 8 and 41 characters")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "database_password", "RDS Cluster database password should be between 8 and 41 characters")
 		}
 
 		for _, c := range r.DatabasePassword {
 			if unicode.IsSymbol(c) || unicode.IsMark(c) {
-				return fmt.Errorf("RDS Cluster database password contains an offending character: '%c'", c)
+				return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "database_password", fmt.Sprintf("RDS Cluster database password contains an offending character: '%c'", c))
 			}
 		}
 	}
 
 	if r.Port != nil {
 		if *r.Port < 1150 || *r.Port > 65535 {
-			return errors.New("RDS Cluster port number should be between 1150 and 65535")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "port", "RDS Cluster port number should be between 1150 and 65535")
 		}
 	}
 
 	if r.BackupRetention != nil {
 		if *r.BackupRetention < 1 || *r.BackupRetention > 35 {
-			return errors.New("RDS Cluster backup retention should be between 1 and 35 days")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "backup_retention", "RDS Cluster backup retention should be between 1 and 35 days")
 		}
 	}
 
@@ -256,17 +256,17 @@ func (r *RDSCluster) Validate() error {
 
 		err := validateTimeFormat(parts[0])
 		if err != nil {
-			return errors.New("RDS Cluster backup window: " + err.Error())
+			return withField(err, "backup_window", "RDS Cluster backup window: ")
 		}
 
 		err = validateTimeFormat(parts[1])
 		if err != nil {
-			return errors.New("RDS Cluster backup window: " + err.Error())
+			return withField(err, "backup_window", "RDS Cluster backup window: ")
 		}
 	}
 
 	if mwerr := validateTimeWindow(r.MaintenanceWindow); r.MaintenanceWindow != "" && mwerr != nil {
-		return fmt.Errorf("RDS Cluster maintenance window: %s", mwerr.Error())
+		return withField(mwerr, "maintenance_window", "RDS Cluster maintenance window: ")
 	}
 
 	return nil
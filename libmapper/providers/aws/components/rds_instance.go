@@ -5,11 +5,11 @@
 package components
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"unicode"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -51,7 +51,7 @@ type RDSInstance struct {
 	NetworkAWSIDs       []string          `json:"network_aws_ids" diff:"-"`
 	DatabaseName        string            `json:"database_name,omitempty" diff:"database_name,immutable"`
 	DatabaseUsername    string            `json:"database_username,omitempty" diff:"database_username,immutable"`
-	DatabasePassword    string            `json:"database_password,omitempty" diff:"database_password"`
+	DatabasePassword    string            `json:"database_password,omitempty" diff:"database_password" sensitive:"true"`
 	AutoUpgrade         bool              `json:"auto_upgrade" diff:"auto_upgrade"`
 	BackupRetention     *int64            `json:"backup_retention,omitempty" diff:"backup_retention"`
 	BackupWindow        string            `json:"backup_window,omitempty" diff:"backup_window"`
@@ -65,7 +65,7 @@ type RDSInstance struct {
 	DatacenterName      string            `json:"datacenter_name" diff:"-"`
 	DatacenterRegion    string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID         string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey     string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey     string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service             string            `json:"service" diff:"-"`
 }
 
@@ -222,19 +222,19 @@ func (r *RDSInstance) SequentialDependencies() []string {
 // Validate : validates the components values
 func (r *RDSInstance) Validate() error {
 	if r.Name == "" {
-		return errors.New("RDS Instance name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "RDS Instance name should not be null")
 	}
 
 	if len(r.Name) > 255 {
-		return errors.New("RDS Instance name should not exceed 255 characters")
+		return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "name", "RDS Instance name should not exceed 255 characters")
 	}
 
 	if r.Size == "" {
-		return errors.New("RDS Instance size should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "size", "RDS Instance size should not be null")
 	}
 
 	if r.Size[:3] != "db." {
-		return errors.New("RDS Instance size should be a valid resource size. i.e. 'db.r3.large'")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "size", "RDS Instance size should be a valid resource size. i.e. 'db.r3.large'")
 	}
 
 	err := r.validateReplication()
@@ -295,47 +295,47 @@ func (r *RDSInstance) SetDefaultVariables() {
 func (r *RDSInstance) validateReplication() error {
 	if r.ReplicationSource != "" {
 		if r.Engine != "" {
-			return errors.New("RDS Instance must not specify an engine if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "engine", "RDS Instance must not specify an engine if a replication source is set")
 		}
 
 		if r.EngineVersion != "" {
-			return errors.New("RDS Instance must not specify an engine version if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "engine_version", "RDS Instance must not specify an engine version if a replication source is set")
 		}
 
 		if r.StorageSize != nil {
-			return errors.New("RDS Instance must not specify storage size if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "storage_size", "RDS Instance must not specify storage size if a replication source is set")
 		}
 
 		if r.Cluster != "" {
-			return errors.New("RDS Instance must not specify a cluster if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "cluster", "RDS Instance must not specify a cluster if a replication source is set")
 		}
 
 		if r.MultiAZ == true {
-			return errors.New("RDS Instance must not specify multi az standby instance if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "multi_az", "RDS Instance must not specify multi az standby instance if a replication source is set")
 		}
 
 		if r.PromotionTier != nil {
-			return errors.New("RDS Instance must not specify promotion tier if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "promotion_tier", "RDS Instance must not specify promotion tier if a replication source is set")
 		}
 
 		if r.DatabaseName != "" {
-			return errors.New("RDS Instance must not specify database name if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "database_name", "RDS Instance must not specify database name if a replication source is set")
 		}
 
 		if r.DatabaseUsername != "" {
-			return errors.New("RDS Instance must not specify database username if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "database_username", "RDS Instance must not specify database username if a replication source is set")
 		}
 
 		if r.DatabasePassword != "" {
-			return errors.New("RDS Instance must not specify database password if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "database_password", "RDS Instance must not specify database password if a replication source is set")
 		}
 
 		if r.License != "" {
-			return errors.New("RDS Instance must not specify a license type if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "license", "RDS Instance must not specify a license type if a replication source is set")
 		}
 
 		if r.Timezone != "" {
-			return errors.New("RDS Instance must not specify a timezone if a replication source is set")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "timezone", "RDS Instance must not specify a timezone if a replication source is set")
 		}
 	}
 
@@ -345,7 +345,7 @@ func (r *RDSInstance) validateReplication() error {
 func (r *RDSInstance) validateBackups() error {
 	if r.BackupRetention != nil {
 		if *r.BackupRetention < 1 || *r.BackupRetention > 35 {
-			return errors.New("RDS Instance backup retention should be between 1 and 35 days")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "backup_retention", "RDS Instance backup retention should be between 1 and 35 days")
 		}
 	}
 
@@ -354,12 +354,12 @@ func (r *RDSInstance) validateBackups() error {
 
 		err := validateTimeFormat(parts[0])
 		if err != nil {
-			return errors.New("RDS Instance backup window: " + err.Error())
+			return withField(err, "backup_window", "RDS Instance backup window: ")
 		}
 
 		err = validateTimeFormat(parts[1])
 		if err != nil {
-			return errors.New("RDS Instance backup window: " + err.Error())
+			return withField(err, "backup_window", "RDS Instance backup window: ")
 		}
 	}
 
@@ -368,12 +368,12 @@ func (r *RDSInstance) validateBackups() error {
 
 func (r *RDSInstance) validatePort() error {
 	if r.Cluster != "" && r.Port != nil {
-		return fmt.Errorf("RDS Instance port should be set on cluster")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "port", "RDS Instance port should be set on cluster")
 	}
 
 	if r.Port != nil {
 		if *r.Port < 1150 || *r.Port > 65535 {
-			return errors.New("RDS Instance port number should be between 1150 and 65535")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "port", "RDS Instance port number should be between 1150 and 65535")
 		}
 	}
 
@@ -383,42 +383,42 @@ func (r *RDSInstance) validatePort() error {
 func (r *RDSInstance) validateDatabase() error {
 	if r.Cluster != "" {
 		if r.DatabaseName != "" {
-			return errors.New("RDS Instance database name should be set on cluster")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "database_name", "RDS Instance database name should be set on cluster")
 		}
 
 		if r.DatabaseUsername != "" {
-			return errors.New("RDS Instance database username should be set on cluster")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "database_username", "RDS Instance database username should be set on cluster")
 		}
 
 		if r.DatabasePassword != "" {
-			return errors.New("RDS Instance database password should be set on cluster")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "database_password", "RDS Instance database password should be set on cluster")
 		}
 	} else {
 		if r.DatabaseName == "" {
-			return errors.New("RDS Instance database name should not be null")
+			return libmapper.NewFieldError(libmapper.ErrCodeRequired, "database_name", "RDS Instance database name should not be null")
 		}
 
 		if len(r.DatabaseName) > 64 {
-			return errors.New("RDS Instance database name should not exceed 64 characters")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "database_name", "RDS Instance database name should not exceed 64 characters")
 		}
 
 		for _, c := range r.DatabaseName {
 			if unicode.IsLetter(c) != true && unicode.IsNumber(c) != true {
-				return errors.New("RDS Instance database name can only contain alphanumeric characters")
+				return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "database_name", "RDS Instance database name can only contain alphanumeric characters")
 			}
 		}
 
 		if r.DatabaseUsername == "" {
-			return errors.New("RDS Instance database username should not be null")
+			return libmapper.NewFieldError(libmapper.ErrCodeRequired, "database_username", "RDS Instance database username should not be null")
 		}
 
 		if len(r.DatabaseUsername) > 16 {
-			return errors.New("RDS Instance database username should not exceed 16 characters")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "database_username", "RDS Instance database username should not exceed 16 characters")
 		}
 
 		for _, c := range r.DatabasePassword {
 			if unicode.IsSymbol(c) || unicode.IsMark(c) {
-				return fmt.Errorf("RDS Instance database password contains an offending character: '%c'", c)
+				return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "database_password", fmt.Sprintf("RDS Instance database password contains an offending character: '%c'", c))
 			}
 		}
 	}
@@ -429,11 +429,11 @@ func (r *RDSInstance) validateDatabase() error {
 func (r *RDSInstance) validateEngine() error {
 	if r.Cluster != "" {
 		if r.EngineVersion != "" {
-			return fmt.Errorf("RDS Instance engine version should be set on cluster")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "engine_version", "RDS Instance engine version should be set on cluster")
 		}
 	} else {
 		if r.Engine == "" {
-			return errors.New("RDS Instance engine type should not be null")
+			return libmapper.NewFieldError(libmapper.ErrCodeRequired, "engine", "RDS Instance engine type should not be null")
 		}
 	}
 
@@ -443,21 +443,21 @@ func (r *RDSInstance) validateEngine() error {
 func (r *RDSInstance) validateStorage() error {
 	if r.Engine != EngineTypeAurora {
 		if r.StorageType != "" && isOneOf(StorageTypes, r.StorageType) != true {
-			return errors.New("RDS Instance storage type must be either 'standard', 'gp2' or 'io1'")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "storage_type", "RDS Instance storage type must be either 'standard', 'gp2' or 'io1'")
 		}
 		if r.StorageSize != nil {
 			if *r.StorageSize < 5 || *r.StorageSize > 6144 {
-				return errors.New("RDS Instance storage size must be between 5 - 6144 GB")
+				return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "storage_size", "RDS Instance storage size must be between 5 - 6144 GB")
 			}
 		}
 		if r.StorageIops != nil {
 			if (*r.StorageIops % 1000) != 0 {
-				return errors.New("RDS Instance storage iops must be a multiple of 1000")
+				return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "storage_iops", "RDS Instance storage iops must be a multiple of 1000")
 			}
 		}
 	} else {
 		if r.StorageType != "" || r.StorageSize != nil || r.StorageIops != nil {
-			return errors.New("RDS Instance storage options cannot be set if the engine type is 'aurora'")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "storage_type", "RDS Instance storage options cannot be set if the engine type is 'aurora'")
 		}
 	}
 
@@ -467,27 +467,27 @@ func (r *RDSInstance) validateStorage() error {
 func (r *RDSInstance) validateOther() error {
 	if r.PromotionTier != nil {
 		if r.Engine != EngineTypeAurora {
-			return errors.New("RDS Instance promotion tier should only be specified when using the aurora engine")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "promotion_tier", "RDS Instance promotion tier should only be specified when using the aurora engine")
 		}
 		if *r.PromotionTier < 0 || *r.PromotionTier > 15 {
-			return errors.New("RDS Instance promotion tier should be between 0 - 15")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "promotion_tier", "RDS Instance promotion tier should be between 0 - 15")
 		}
 	}
 
 	if r.AvailabilityZone != "" && r.MultiAZ {
-		return errors.New("RDS Instance cannot specify both an availability zone and a multi az standby instance")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "availability_zone", "RDS Instance cannot specify both an availability zone and a multi az standby instance")
 	}
 
 	if mwerr := validateTimeWindow(r.MaintenanceWindow); r.MaintenanceWindow != "" && mwerr != nil {
-		return fmt.Errorf("RDS Instance maintenance window: %s", mwerr.Error())
+		return withField(mwerr, "maintenance_window", "RDS Instance maintenance window: ")
 	}
 
 	if r.Public == false && len(r.Networks) < 1 && r.Cluster == "" {
-		return errors.New("RDS Instance should specify at least one network if not set to public")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "networks", "RDS Instance should specify at least one network if not set to public")
 	}
 
 	if r.Engine != EngineTypeAurora && r.Engine != "" && isOneOf(Licenses, r.License) != true {
-		return errors.New("RDS Instance license must be one of 'license-included', 'bring-your-own-license', 'general-public-license'")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "license", "RDS Instance license must be one of 'license-included', 'bring-your-own-license', 'general-public-license'")
 	}
 
 	return nil
@@ -5,10 +5,11 @@
 package components
 
 import (
-	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -51,7 +52,7 @@ type Route53Zone struct {
 	DatacenterName   string            `json:"datacenter_name" diff:"-"`
 	DatacenterRegion string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID      string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service          string            `json:"service" diff:"-"`
 }
 
@@ -273,24 +274,24 @@ func (z *Route53Zone) SequentialDependencies() []string {
 // Validate : validates the components values
 func (z *Route53Zone) Validate() error {
 	if z.Name == "" {
-		return errors.New("Route53 zone name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "Route53 zone name should not be null")
 	}
 
 	if z.Private && z.Vpc == "" {
-		return errors.New("Route53 private zone must specify a vpc!")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "vpc", "Route53 private zone must specify a vpc!")
 	}
 
-	for _, record := range z.Records {
+	for i, record := range z.Records {
 		if record.Entry == "" {
-			return errors.New("Route53 record entry name should not be null")
+			return libmapper.NewFieldError(libmapper.ErrCodeRequired, "records["+strconv.Itoa(i)+"].entry", "Route53 record entry name should not be null")
 		}
 
 		if !isOneOf(DNSTYPES, record.Type) {
-			return fmt.Errorf("Route53 record type '%s' is not a valid dns type. Please use one of [%s]", record.Type, strings.Join(DNSTYPES, ", "))
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "records["+strconv.Itoa(i)+"].type", fmt.Sprintf("Route53 record type '%s' is not a valid dns type. Please use one of [%s]", record.Type, strings.Join(DNSTYPES, ", ")))
 		}
 
 		if record.TTL == 0 {
-			return errors.New("Route53 record TTL must be greater than 0")
+			return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "records["+strconv.Itoa(i)+"].ttl", "Route53 record TTL must be greater than 0")
 		}
 	}
 
@@ -5,10 +5,11 @@
 package components
 
 import (
-	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -46,7 +47,7 @@ type S3Bucket struct {
 	DatacenterName   string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID      string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service          string            `json:"service" diff:"-"`
 }
 
@@ -148,32 +149,32 @@ func (s3 *S3Bucket) SequentialDependencies() []string {
 // Validate : validates the components values
 func (s3 *S3Bucket) Validate() error {
 	if s3.Name == "" {
-		return errors.New("S3 bucket name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "S3 bucket name should not be null")
 	}
 
 	if s3.BucketLocation == "" {
-		return errors.New("S3 bucket location should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "bucket_location", "S3 bucket location should not be null")
 	}
 
 	if s3.ACL != "" && len(s3.Grantees) > 0 {
-		return errors.New("S3 bucket must specify either acl or grantees, not both")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "grantees", "S3 bucket must specify either acl or grantees, not both")
 	}
 
 	if s3.ACL != "" && isOneOf(S3ACLTYPES, s3.ACL) == false {
-		return fmt.Errorf("S3 bucket ACL (%s) is not valid. Must be one of [%s]", s3.ACL, strings.Join(S3ACLTYPES, " | "))
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "acl", fmt.Sprintf("S3 bucket ACL (%s) is not valid. Must be one of [%s]", s3.ACL, strings.Join(S3ACLTYPES, " | ")))
 	}
 
-	for _, g := range s3.Grantees {
+	for i, g := range s3.Grantees {
 		if isOneOf(S3GRANTEETYPES, g.Type) == false {
-			return fmt.Errorf("S3 grantee type (%s) is invalid", g.Type)
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "grantees["+strconv.Itoa(i)+"].type", fmt.Sprintf("S3 grantee type (%s) is invalid", g.Type))
 		}
 
 		if g.ID == "" {
-			return fmt.Errorf("S3 grantee id should not be null")
+			return libmapper.NewFieldError(libmapper.ErrCodeRequired, "grantees["+strconv.Itoa(i)+"].id", "S3 grantee id should not be null")
 		}
 
 		if isOneOf(S3PERMISSIONTYPES, g.Permissions) == false {
-			return fmt.Errorf("S3 grantee permissions (%s) is not valid. Must be one of [%s]", s3.ACL, strings.ToLower(strings.Join(S3PERMISSIONTYPES, " | ")))
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, "grantees["+strconv.Itoa(i)+"].permissions", fmt.Sprintf("S3 grantee permissions (%s) is not valid. Must be one of [%s]", s3.ACL, strings.ToLower(strings.Join(S3PERMISSIONTYPES, " | "))))
 		}
 	}
 	return nil
@@ -5,8 +5,9 @@
 package components
 
 import (
-	"errors"
+	"strconv"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -37,7 +38,7 @@ type SecurityGroup struct {
 	DatacenterName   string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID      string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Vpc              string            `json:"vpc" diff:"-"`
 	VpcID            string            `json:"vpc_id" diff:"-"`
 	Service          string            `json:"service" diff:"-"`
@@ -147,24 +148,24 @@ func (sg *SecurityGroup) Dependencies() []string {
 // Validate : validates the components values
 func (sg *SecurityGroup) Validate() error {
 	if sg.Name == "" {
-		return errors.New("Security Group name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "Security Group name should not be null")
 	}
 
 	if sg.Vpc == "" {
-		return errors.New("Secuirty Group must specify a vpc")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "vpc", "Secuirty Group must specify a vpc")
 	}
 
-	for _, rule := range sg.Rules.Ingress {
+	for i, rule := range sg.Rules.Ingress {
 		err := rule.Validate()
 		if err != nil {
-			return err
+			return withField(err, "rules.ingress["+strconv.Itoa(i)+"]", "")
 		}
 	}
 
-	for _, rule := range sg.Rules.Egress {
+	for i, rule := range sg.Rules.Egress {
 		err := rule.Validate()
 		if err != nil {
-			return err
+			return withField(err, "rules.egress["+strconv.Itoa(i)+"]", "")
 		}
 	}
 
@@ -199,21 +200,21 @@ func (rule *SecurityGroupRule) Validate() error {
 	// Must be: [0 - 65535]
 	err := validatePort(rule.From, "Security Group From")
 	if err != nil {
-		return err
+		return withField(err, "from_port", "")
 	}
 
 	// Validate ToPort Port
 	// Must be: [0 - 65535]
 	err = validatePort(rule.To, "Security Group To")
 	if err != nil {
-		return err
+		return withField(err, "to_port", "")
 	}
 
 	// Validate Protocol
 	// Must be one of: tcp | udp | icmp | any | tcp & udp
 	err = validateProtocol(rule.Protocol)
 	if err != nil {
-		return err
+		return withField(err, "protocol", "")
 	}
 
 	return nil
@@ -5,10 +5,11 @@
 package components
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/ernestio/definition-mapper/libmapper"
 )
 
 const (
@@ -47,13 +48,13 @@ func validateProtocol(p string) error {
 	case PROTOCOLTCP, PROTOCOLUDP, PROTOCOLICMP, PROTOCOLANY:
 		return nil
 	}
-	return errors.New("Protocol is invalid")
+	return libmapper.NewError(libmapper.ErrCodeInvalidValue, "Protocol is invalid")
 }
 
 // ValidatePort checks an string to be a valid TCP port
 func validatePort(port int, ptype string) error {
 	if port < 0 || port > 65535 {
-		return fmt.Errorf("%s Port (%d) is out of range [0 - 65535]", ptype, port)
+		return libmapper.NewError(libmapper.ErrCodeOutOfRange, fmt.Sprintf("%s Port (%d) is out of range [0 - 65535]", ptype, port))
 	}
 
 	return nil
@@ -65,24 +66,24 @@ func validateDateTimeFormat(t string) error {
 
 	parts := strings.Split(t, ":")
 	if len(parts) != 3 {
-		return errors.New("Date format must take the form of 'ddd:hh24:mi'. i.e. 'Mon:21:30'")
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Date format must take the form of 'ddd:hh24:mi'. i.e. 'Mon:21:30'")
 	}
 
 	// is valid day
 	if isOneOf(days, parts[0]) != true {
-		return fmt.Errorf("Date format invalid. Day must be one of %s", strings.Join(days, ", "))
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Date format invalid. Day must be one of "+strings.Join(days, ", "))
 	}
 
 	// is valid hour
 	d, err := strconv.Atoi(parts[1])
 	if err != nil || d < 0 || d > 23 {
-		return errors.New("Date format invalid. Hour must be between 0 and 23 hours")
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Date format invalid. Hour must be between 0 and 23 hours")
 	}
 
 	// is valid minute
 	d, err = strconv.Atoi(parts[2])
 	if err != nil || d < 0 || d > 59 {
-		return errors.New("Date format invalid. Minute must be between 0 and 59 minutes")
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Date format invalid. Minute must be between 0 and 59 minutes")
 	}
 
 	return nil
@@ -91,18 +92,18 @@ func validateDateTimeFormat(t string) error {
 func validateTimeFormat(t string) error {
 	parts := strings.Split(t, ":")
 	if len(parts) != 2 {
-		return errors.New("Time format must take the form of 'hh24:mi-hh24:mi'. i.e. '21:30-22:00'")
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Time format must take the form of 'hh24:mi-hh24:mi'. i.e. '21:30-22:00'")
 	}
 	// is valid hour
 	d, err := strconv.Atoi(parts[0])
 	if err != nil || d < 0 || d > 23 {
-		return errors.New("Time format invalid. Hour must be between 0 and 23 hours")
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Time format invalid. Hour must be between 0 and 23 hours")
 	}
 
 	// is valid minute
 	d, err = strconv.Atoi(parts[1])
 	if err != nil || d < 0 || d > 59 {
-		return errors.New("Time format invalid. Minute must be between 0 and 59 minutes")
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Time format invalid. Minute must be between 0 and 59 minutes")
 	}
 
 	return nil
@@ -111,7 +112,7 @@ func validateTimeFormat(t string) error {
 func validateTimeWindow(w string) error {
 	p := strings.Split(w, "-")
 	if len(p) != 2 {
-		return errors.New("Window format must take the form of 'ddd:hh24:mi-ddd:hh24:mi'. i.e. 'Mon:21:30-Mon:22:00'")
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, "Window format must take the form of 'ddd:hh24:mi-ddd:hh24:mi'. i.e. 'Mon:21:30-Mon:22:00'")
 	}
 
 	err := validateDateTimeFormat(p[0])
@@ -139,3 +140,21 @@ func isOneOf(values []string, value string) bool {
 	}
 	return false
 }
+
+// withField : returns a validation error for a field, keeping the code of
+// typed errors. Fields already set on the error are nested under the field
+func withField(err error, field, prefix string) libmapper.Error {
+	e, ok := err.(libmapper.Error)
+	if !ok {
+		e = libmapper.NewError(libmapper.ErrCodeInvalidValue, err.Error())
+	}
+
+	if e.Field != "" {
+		field = field + "." + e.Field
+	}
+
+	e.Field = field
+	e.Message = prefix + e.Message
+
+	return e
+}
@@ -0,0 +1,39 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/stretchr/testify/suite"
+)
+
+// ValidationTestSuite : Test suite for component validation errors
+type ValidationTestSuite struct {
+	suite.Suite
+}
+
+// TestFieldErrors : Testing validation failures are typed on the field that failed
+func (suite *ValidationTestSuite) TestFieldErrors() {
+	sg := SecurityGroup{Name: "web", Vpc: "main"}
+	sg.Rules.Egress = []SecurityGroupRule{{From: 80, To: 80, Protocol: "tcp"}, {From: 80, To: 70000, Protocol: "tcp"}}
+
+	err := sg.Validate().(libmapper.Error)
+	suite.Equal(libmapper.ErrCodeOutOfRange, err.Code)
+	suite.Equal("rules.egress[1].to_port", err.Field)
+
+	err = (&Vpc{Name: "main"}).Validate().(libmapper.Error)
+	suite.Equal(libmapper.ErrCodeRequired, err.Code)
+	suite.Equal("subnet", err.Field)
+
+	rds := RDSCluster{Name: "db", Engine: "aurora", DatabaseName: "db", DatabaseUsername: "admin", BackupWindow: "25:00-26:00"}
+
+	err = rds.Validate().(libmapper.Error)
+	suite.Equal(libmapper.ErrCodeInvalidFormat, err.Code)
+	suite.Equal("backup_window", err.Field)
+	suite.Contains(err.Message, "RDS Cluster backup window: ")
+}
+
+// TestValidationTestSuite : Test suite for component validation errors
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
@@ -5,8 +5,7 @@
 package components
 
 import (
-	"errors"
-
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -27,7 +26,7 @@ type Vpc struct {
 	DatacenterName   string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion string            `json:"datacenter_region" diff:"-"`
 	AccessKeyID      string            `json:"aws_access_key_id" diff:"-"`
-	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-"`
+	SecretAccessKey  string            `json:"aws_secret_access_key" diff:"-" sensitive:"true"`
 	Service          string            `json:"service" diff:"-"`
 }
 
@@ -119,10 +118,10 @@ func (v *Vpc) SequentialDependencies() []string {
 // Validate : validates the components values
 func (v *Vpc) Validate() error {
 	if v.Name == "" {
-		return errors.New("vpc must specify a name")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "vpc must specify a name")
 	}
 	if v.Subnet == "" && v.VpcAWSID == "" {
-		return errors.New("vpc must specify either subnet or an existing vpc id")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "subnet", "vpc must specify either subnet or an existing vpc id")
 	}
 
 	return nil
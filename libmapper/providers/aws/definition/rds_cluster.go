@@ -21,7 +21,7 @@ type RDSCluster struct {
 	Networks          []string  `json:"networks" yaml:"networks"`
 	DatabaseName      string    `json:"database_name" yaml:"database_name"`
 	DatabaseUsername  string    `json:"database_username" yaml:"database_username"`
-	DatabasePassword  string    `json:"database_password" yaml:"database_password"`
+	DatabasePassword  string    `json:"database_password" yaml:"database_password" sensitive:"true"`
 	Backups           RDSBackup `json:"backups" yaml:"backups"`
 	MaintenanceWindow string    `json:"maintenance_window" yaml:"maintenance_window"`
 	ReplicationSource string    `json:"replication_source" yaml:"replication_source"`
@@ -28,7 +28,7 @@ type RDSInstance struct {
 	Networks          []string   `json:"networks" yaml:"networks"`
 	DatabaseName      string     `json:"database_name" yaml:"database_name"`
 	DatabaseUsername  string     `json:"database_username" yaml:"database_username"`
-	DatabasePassword  string     `json:"database_password" yaml:"database_password"`
+	DatabasePassword  string     `json:"database_password" yaml:"database_password" sensitive:"true"`
 	AutoUpgrade       bool       `json:"auto_upgrade" yaml:"auto_upgrade"`
 	Backups           RDSBackup  `json:"backups" yaml:"backups"`
 	MaintenanceWindow string     `json:"maintenance_window" yaml:"maintenance_window"`
@@ -0,0 +1,271 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/r3labs/graph"
+)
+
+const (
+	// FILTERTAG : imports resources tagged with a key and value, i.e. 'tag:team=payments'
+	FILTERTAG = "tag:"
+	// FILTERTYPE : imports only the given component types, i.e. 'type:instance,ebs_volume'
+	FILTERTYPE = "type:"
+	// FILTERVPC : imports only resources within a vpc, i.e. 'vpc:vpc-0a1b2c3d'
+	FILTERVPC = "vpc:"
+)
+
+// VPCCOMPONENTS : component types that are imported from within a vpc
+var VPCCOMPONENTS = []string{"vpc", "internet_gateway", "network", "instance", "firewall", "nat", "elb", "rds_instance", "rds_cluster"}
+
+// ATTACHEDCOMPONENTS : component types that are imported along with the
+// instances they are attached to when filtering by vpc
+var ATTACHEDCOMPONENTS = []string{"ebs_volume"}
+
+// ImportFilter : the resources an import is restricted to
+type ImportFilter struct {
+	Tags  map[string]string
+	Types []string
+	VpcID string
+}
+
+// ParseImportFilters : parses the filters of an import request. Tag filters
+// must all match. Filters without a prefix are service names, matching the
+// 'ernest.service' tag of resources created by ernest. Invalid filters are
+// returned as errors and left out of the returned filter
+func ParseImportFilters(params []string) (*ImportFilter, libmapper.Errors) {
+	var errs libmapper.Errors
+
+	f := ImportFilter{
+		Tags: make(map[string]string),
+	}
+
+	for i, p := range params {
+		field := "filters[" + strconv.Itoa(i) + "]"
+
+		switch {
+		case strings.HasPrefix(p, FILTERTAG):
+			kv := strings.SplitN(strings.TrimPrefix(p, FILTERTAG), "=", 2)
+			if len(kv) < 2 || kv[0] == "" {
+				errs = append(errs, libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, field, "Tag filter ("+p+") must be in the format 'tag:key=value'"))
+				continue
+			}
+
+			f.Tags[kv[0]] = kv[1]
+		case strings.HasPrefix(p, FILTERTYPE):
+			for _, t := range strings.Split(strings.TrimPrefix(p, FILTERTYPE), ",") {
+				ctype, ok := importType(strings.TrimSpace(t))
+				if !ok {
+					errs = append(errs, libmapper.NewFieldError(libmapper.ErrCodeInvalidValue, field, "Type filter ("+t+") is not a component type that can be imported. Must be one of ["+strings.Join(importTypes(), " | ")+"]"))
+					continue
+				}
+
+				f.Types = appendUnique(f.Types, ctype)
+			}
+		case strings.HasPrefix(p, FILTERVPC):
+			f.VpcID = strings.TrimPrefix(p, FILTERVPC)
+			if f.VpcID == "" {
+				errs = append(errs, libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, field, "Vpc filter must be in the format 'vpc:vpc-id'"))
+			}
+		case p != "":
+			f.Tags["ernest.service"] = p
+		}
+	}
+
+	return &f, errs
+}
+
+// GroupComponents : groups imported instances and ebs volumes that were not created by ernest
+func (m Mapper) GroupComponents(g *graph.Graph) libmapper.Errors {
+	warnings := libmapper.GroupComponents(g, components.TYPEINSTANCE, libmapper.Grouping{
+		Tag: components.GROUPINSTANCE,
+		Signature: func(c graph.Component) string {
+			i := c.(*components.Instance)
+			sgs := append([]string{}, i.SecurityGroups...)
+			sort.Strings(sgs)
+			return fmt.Sprint(i.Type, i.Image, i.Network, i.KeyPair, sgs, i.IAMInstanceProfile != nil && *i.IAMInstanceProfile != "", i.ElasticIP != "")
+		},
+		IP: func(c graph.Component) string {
+			return c.(*components.Instance).IP
+		},
+		SetGroup: func(c graph.Component, group string) {
+			i := c.(*components.Instance)
+			if i.Tags == nil {
+				i.Tags = make(map[string]string)
+			}
+			i.Tags[components.GROUPINSTANCE] = group
+		},
+	})
+
+	return append(warnings, libmapper.GroupComponents(g, components.TYPEEBSVOLUME, libmapper.Grouping{
+		Tag: components.GROUPEBSVOLUME,
+		Signature: func(c graph.Component) string {
+			v := c.(*components.EBSVolume)
+			return fmt.Sprint(v.VolumeType, v.AvailabilityZone, v.Encrypted, deref(v.Size), deref(v.Iops))
+		},
+		SetGroup: func(c graph.Component, group string) {
+			v := c.(*components.EBSVolume)
+			if v.Tags == nil {
+				v.Tags = make(map[string]string)
+			}
+			v.Tags[components.GROUPEBSVOLUME] = group
+		},
+	})...)
+}
+
+func deref(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
+
+// FilterImport : removes the components of a completed import that are
+// outside the vpc it was filtered by, as connectors may not restrict their
+// queries to it. Instances, nats, elbs and rds are matched to the vpc by
+// their networks, and ebs volumes by the instances they are attached to, so
+// they are only removed when those were imported too
+func (m Mapper) FilterImport(g *graph.Graph, params []string) {
+	f, _ := ParseImportFilters(params)
+	if f.VpcID == "" {
+		return
+	}
+
+	types := f.queryTypes()
+
+	var networks, volumes map[string]bool
+
+	if isOneOf(types, components.TYPENETWORK) {
+		networks = make(map[string]bool)
+
+		for _, c := range g.GetComponents().ByType(components.TYPENETWORK) {
+			n := c.(*components.Network)
+			if n.VpcID == f.VpcID {
+				networks[n.NetworkAWSID] = true
+			}
+		}
+	}
+
+	if networks != nil && isOneOf(types, components.TYPEINSTANCE) {
+		volumes = make(map[string]bool)
+
+		for _, c := range g.GetComponents().ByType(components.TYPEINSTANCE) {
+			i := c.(*components.Instance)
+			if !networks[i.NetworkAWSID] {
+				continue
+			}
+
+			for _, v := range i.Volumes {
+				volumes[v.VolumeAWSID] = true
+			}
+		}
+	}
+
+	for _, c := range append(graph.ComponentGroup{}, g.Components...) {
+		if !f.inVpc(c, networks, volumes) {
+			g.DeleteComponent(c)
+		}
+	}
+}
+
+// inVpc : returns false if a component is known to be outside the filtered
+// vpc. Networks and volumes are nil when they were not imported
+func (f *ImportFilter) inVpc(c graph.Component, networks, volumes map[string]bool) bool {
+	switch x := c.(type) {
+	case *components.Vpc:
+		return x.VpcAWSID == f.VpcID
+	case *components.Network:
+		return x.VpcID == f.VpcID
+	case *components.SecurityGroup:
+		return x.VpcID == f.VpcID
+	case *components.InternetGateway:
+		return x.VpcID == f.VpcID
+	case *components.Instance:
+		return networks == nil || networks[x.NetworkAWSID]
+	case *components.NatGateway:
+		return networks == nil || networks[x.PublicNetworkAWSID]
+	case *components.ELB:
+		return networks == nil || anyOf(networks, x.NetworkAWSIDs)
+	case *components.RDSInstance:
+		return networks == nil || anyOf(networks, x.NetworkAWSIDs)
+	case *components.RDSCluster:
+		return networks == nil || anyOf(networks, x.NetworkAWSIDs)
+	case *components.EBSVolume:
+		return volumes == nil || volumes[x.VolumeAWSID]
+	}
+
+	return true
+}
+
+func anyOf(set map[string]bool, values []string) bool {
+	for _, v := range values {
+		if set[v] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// queryTypes : returns the component types to query. Resources that do not
+// belong to a vpc are not imported when filtering by vpc, unless their type
+// has been requested
+func (f *ImportFilter) queryTypes() []string {
+	switch {
+	case len(f.Types) > 0:
+		return f.Types
+	case f.VpcID != "":
+		return append(append([]string{}, VPCCOMPONENTS...), ATTACHEDCOMPONENTS...)
+	}
+
+	return SUPPORTEDCOMPONENTS
+}
+
+// importType : returns the query type of a component type, as listed on SUPPORTEDCOMPONENTS
+func importType(ctype string) (string, bool) {
+	if ctype == components.TYPEIAMPOLICY {
+		ctype = "iam_policie"
+	}
+
+	for _, t := range SUPPORTEDCOMPONENTS {
+		if t == ctype {
+			return t, true
+		}
+	}
+
+	return "", false
+}
+
+func isOneOf(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// importTypes : returns the component types that can be imported
+func importTypes() []string {
+	types := make([]string, len(SUPPORTEDCOMPONENTS))
+
+	for i, t := range SUPPORTEDCOMPONENTS {
+		if t == "iam_policie" {
+			t = components.TYPEIAMPOLICY
+		}
+		types[i] = t
+	}
+
+	return types
+}
@@ -0,0 +1,83 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// ImportTestSuite : Test suite for import filters
+type ImportTestSuite struct {
+	suite.Suite
+	Mapper Mapper
+}
+
+// TestParseImportFilters : Testing the parsing of import filters
+func (suite *ImportTestSuite) TestParseImportFilters() {
+	f, errs := ParseImportFilters([]string{"tag:team=payments", "tag:env=prod", "type:instance, iam_policy", "vpc:vpc-0a1b"})
+	suite.Len(errs, 0)
+	suite.Equal(map[string]string{"team": "payments", "env": "prod"}, f.Tags)
+	suite.Equal([]string{"instance", "iam_policie"}, f.Types)
+	suite.Equal("vpc-0a1b", f.VpcID)
+
+	f, errs = ParseImportFilters([]string{"web"})
+	suite.Len(errs, 0)
+	suite.Equal(map[string]string{"ernest.service": "web"}, f.Tags)
+	suite.Equal(SUPPORTEDCOMPONENTS, f.queryTypes())
+
+	_, errs = ParseImportFilters([]string{"tag:team", "type:bucket", "vpc:"})
+	suite.Len(errs, 3)
+	suite.Equal("filters[1]", errs[1].Field)
+}
+
+// TestImportFilters : Testing imports are filtered by the service name unless filters are set
+func (suite *ImportTestSuite) TestImportFilters() {
+	suite.Equal([]string{"payments"}, suite.Mapper.ImportFilters(nil, "payments"))
+	suite.Equal([]string{"tag:team=payments"}, suite.Mapper.ImportFilters([]string{"tag:team=payments"}, "payments"))
+}
+
+// TestCreateImportGraph : Testing import queries are restricted by filters
+func (suite *ImportTestSuite) TestCreateImportGraph() {
+	g := suite.Mapper.CreateImportGraph([]string{"tag:team=payments", "vpc:vpc-0a1b"})
+	suite.Len(g.Components, len(VPCCOMPONENTS)+len(ATTACHEDCOMPONENTS))
+
+	q := g.Components[0].(*components.Query)
+	suite.Equal("vpcs", q.ComponentType)
+	suite.Equal("vpc-0a1b", q.VpcID)
+	suite.Equal(map[string]string{"team": "payments"}, q.Tags)
+
+	g = suite.Mapper.CreateImportGraph([]string{"type:s3,instance", "vpc:vpc-0a1b"})
+	suite.Len(g.Components, 2)
+	suite.Equal("", g.Components[0].(*components.Query).VpcID)
+	suite.Equal("vpc-0a1b", g.Components[1].(*components.Query).VpcID)
+}
+
+// TestFilterImport : Testing imported components outside the filtered vpc are removed
+func (suite *ImportTestSuite) TestFilterImport() {
+	g := graph.New()
+	g.AddComponent(&components.Vpc{ComponentType: "vpc", ComponentID: "vpc::main", VpcAWSID: "vpc-0a1b"})
+	g.AddComponent(&components.Vpc{ComponentType: "vpc", ComponentID: "vpc::other", VpcAWSID: "vpc-0c1d"})
+	g.AddComponent(&components.Network{ComponentType: "network", ComponentID: "network::web", NetworkAWSID: "subnet-1", VpcID: "vpc-0a1b"})
+	g.AddComponent(&components.Network{ComponentType: "network", ComponentID: "network::other", NetworkAWSID: "subnet-2", VpcID: "vpc-0c1d"})
+	g.AddComponent(&components.Instance{ComponentType: "instance", ComponentID: "instance::web-1", NetworkAWSID: "subnet-1", Volumes: []components.InstanceVolume{{VolumeAWSID: "vol-1"}}})
+	g.AddComponent(&components.Instance{ComponentType: "instance", ComponentID: "instance::other-1", NetworkAWSID: "subnet-2", Volumes: []components.InstanceVolume{{VolumeAWSID: "vol-2"}}})
+	g.AddComponent(&components.EBSVolume{ComponentType: "ebs_volume", ComponentID: "ebs_volume::web-1", VolumeAWSID: "vol-1"})
+	g.AddComponent(&components.EBSVolume{ComponentType: "ebs_volume", ComponentID: "ebs_volume::other-1", VolumeAWSID: "vol-2"})
+	g.AddComponent(&components.ELB{ComponentType: "elb", ComponentID: "elb::other", NetworkAWSIDs: []string{"subnet-2"}})
+
+	suite.Mapper.FilterImport(g, []string{"vpc:vpc-0a1b"})
+
+	var ids []string
+	for _, c := range g.Components {
+		ids = append(ids, c.GetID())
+	}
+
+	suite.Equal([]string{"vpc::main", "network::web", "instance::web-1", "ebs_volume::web-1"}, ids)
+}
+
+// TestImportTestSuite : Test suite for import filters
+func TestImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ImportTestSuite))
+}
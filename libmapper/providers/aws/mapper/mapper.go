@@ -5,11 +5,10 @@
 package mapper
 
 import (
-	"errors"
-
 	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
 	def "github.com/ernestio/definition-mapper/libmapper/providers/aws/definition"
+	"github.com/ernestio/definition-mapper/logger"
 	"github.com/mitchellh/mapstructure"
 	"github.com/r3labs/graph"
 )
@@ -18,20 +17,35 @@ import (
 var SUPPORTEDCOMPONENTS = []string{"vpc", "internet_gateway", "network", "instance", "firewall", "nat", "elb", "ebs_volume", "s3", "route53", "rds_instance", "rds_cluster", "iam_role", "iam_policie", "iam_instance_profile"}
 
 // Mapper : implements the generic mapper structure
-type Mapper struct{}
+type Mapper struct {
+	logger *logger.Logger
+}
 
 // New : returns a new aws mapper
 func New() libmapper.Mapper {
 	return &Mapper{}
 }
 
+// SetLogger : sets the logger the mapper writes to, so lines can be tied to a request
+func (m *Mapper) SetLogger(l *logger.Logger) {
+	m.logger = l
+}
+
+func (m Mapper) log() *logger.Logger {
+	if m.logger == nil {
+		return logger.Default
+	}
+
+	return m.logger
+}
+
 // ConvertDefinition : converts the input yaml definition to a graph format
 func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error) {
 	g := graph.New()
 
 	d, ok := gd.(*def.Definition)
 	if ok != true {
-		return g, errors.New("Could not convert generic definition into aws format")
+		return g, libmapper.NewError(libmapper.ErrCodeInvalidDefinition, "Could not convert generic definition into aws format")
 	}
 
 	// Map basic component values from definition
@@ -44,15 +58,17 @@ func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error)
 		c.Rebuild(g)
 
 		// Validate Components
+		m.log().Debug("validating component", logger.Fields{"component": c.GetID()})
+
 		err := c.Validate()
 		if err != nil {
-			return g, err
+			return g, libmapper.ComponentError(c, err)
 		}
 
 		// Build internal & template values
 		for _, dep := range c.Dependencies() {
 			if g.HasComponent(dep) != true {
-				return g, errors.New("Component '" + c.GetID() + "': Could not resolve component dependency '" + dep + "'")
+				return g, libmapper.DependencyError(c, dep)
 			}
 		}
 
@@ -65,6 +81,22 @@ func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error)
 	return g, nil
 }
 
+// ValidateDefinition : validates every component of the input definition, collecting all errors found
+func (m Mapper) ValidateDefinition(gd libmapper.Definition) libmapper.Errors {
+	g := graph.New()
+
+	d, ok := gd.(*def.Definition)
+	if ok != true {
+		return libmapper.Errors{libmapper.NewError(libmapper.ErrCodeInvalidDefinition, "Could not convert generic definition into aws format")}
+	}
+
+	var errs libmapper.Errors
+
+	errs = errs.Append(mapComponents(d, g), libmapper.ErrCodeInvalidDefinition)
+
+	return append(errs, libmapper.ValidateGraph(g)...)
+}
+
 // ConvertGraph : converts the service graph into an input yaml format
 func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 	var d def.Definition
@@ -81,13 +113,13 @@ func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 
 		for _, dep := range c.Dependencies() {
 			if g.HasComponent(dep) != true {
-				return g, errors.New("Component '" + c.GetID() + "': Could not resolve component dependency '" + dep + "'")
+				return g, libmapper.DependencyError(c, dep)
 			}
 		}
 
 		err := c.Validate()
 		if err != nil {
-			return d, err
+			return d, libmapper.ComponentError(c, err)
 		}
 	}
 
@@ -106,6 +138,9 @@ func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 	d.IamRoles = MapDefinitionIamRoles(g)
 	d.IamPolicies = MapDefinitionIamPolicies(g)
 
+	// secret values resolved when the definition was mapped are returned as their references
+	libmapper.RestoreSecrets(&d, libmapper.GraphSecretReferences(g))
+
 	return &d, nil
 }
 
@@ -118,6 +153,16 @@ func (m Mapper) LoadDefinition(gd map[string]interface{}) (libmapper.Definition,
 	return &d, err
 }
 
+// UnknownDefinitionKeys : returns an error for every key of the input definition that doesn't match a definition field
+func (m Mapper) UnknownDefinitionKeys(gd map[string]interface{}) libmapper.Errors {
+	return libmapper.UnknownKeys(def.New(), gd)
+}
+
+// ComponentTypes : returns all component types definitions are mapped to
+func (m Mapper) ComponentTypes() []string {
+	return importTypes()
+}
+
 // LoadGraph : returns a generic interal graph
 func (m Mapper) LoadGraph(gg map[string]interface{}) (*graph.Graph, error) {
 	g := graph.New()
@@ -189,20 +234,41 @@ func (m Mapper) LoadGraph(gg map[string]interface{}) (*graph.Graph, error) {
 // CreateImportGraph : creates a new graph with component queries used to import components from a provider
 func (m Mapper) CreateImportGraph(params []string) *graph.Graph {
 	g := graph.New()
-	filter := make(map[string]string)
 
-	if len(params) > 0 {
-		filter["ernest.service"] = params[0]
+	f, errs := ParseImportFilters(params)
+	if len(errs) > 0 {
+		m.log().Warn("ignoring invalid import filters", logger.Fields{"error": errs})
 	}
 
-	for _, ctype := range SUPPORTEDCOMPONENTS {
-		q := MapQuery(ctype+"s", filter)
+	for _, ctype := range f.queryTypes() {
+		q := MapQuery(ctype+"s", f.Tags)
+
+		if f.VpcID != "" && isOneOf(VPCCOMPONENTS, ctype) {
+			q.VpcID = f.VpcID
+		}
+
 		g.AddComponent(q)
 	}
 
 	return g
 }
 
+// ImportFilters : returns the filters an import is queried with, imports are
+// filtered by the service name unless filters are set
+func (m Mapper) ImportFilters(filters []string, service string) []string {
+	if len(filters) > 0 {
+		return filters
+	}
+
+	return []string{service}
+}
+
+// ValidateImportFilters : returns an error for every import filter that is not valid
+func (m Mapper) ValidateImportFilters(params []string) libmapper.Errors {
+	_, errs := ParseImportFilters(params)
+	return errs
+}
+
 // ProviderCredentials : maps aws credentials to a generic component
 func (m Mapper) ProviderCredentials(details map[string]interface{}) graph.Component {
 	credentials := make(graph.GenericComponent)
@@ -221,113 +287,69 @@ func (m Mapper) ProviderCredentials(details map[string]interface{}) graph.Compon
 
 func mapComponents(d *def.Definition, g *graph.Graph) error {
 	// Map basic component values from definition
+	var errs libmapper.Errors
 
 	for _, vpc := range MapVpcs(d) {
-		err := g.AddComponent(vpc)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(vpc), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, gateway := range MapInternetGateways(d) {
-		err := g.AddComponent(gateway)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(gateway), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, network := range MapNetworks(d) {
-		err := g.AddComponent(network)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(network), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, instance := range MapInstances(d) {
-		err := g.AddComponent(instance)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(instance), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, securitygroup := range MapSecurityGroups(d) {
-		err := g.AddComponent(securitygroup)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(securitygroup), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, elb := range MapELBs(d) {
-		err := g.AddComponent(elb)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(elb), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, ebs := range MapEBSVolumes(d) {
-		err := g.AddComponent(ebs)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(ebs), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, nat := range MapNats(d) {
-		err := g.AddComponent(nat)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(nat), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, rds := range MapRDSClusters(d) {
-		err := g.AddComponent(rds)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(rds), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, rds := range MapRDSInstances(d) {
-		err := g.AddComponent(rds)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(rds), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, s3 := range MapS3Buckets(d) {
-		err := g.AddComponent(s3)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(s3), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, route53 := range MapRoute53Zones(d) {
-		err := g.AddComponent(route53)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(route53), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, role := range MapIamRoles(d) {
-		err := g.AddComponent(role)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(role), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, policy := range MapIamPolicies(d) {
-		err := g.AddComponent(policy)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(policy), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, profile := range MapIamInstanceProfiles(d) {
-		err := g.AddComponent(profile)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(profile), libmapper.ErrCodeInvalidDefinition)
 	}
 
-	return nil
+	return errs.Err()
 }
 
 func mapTags(name, service string) map[string]string {
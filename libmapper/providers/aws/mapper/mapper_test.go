@@ -0,0 +1,37 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	def "github.com/ernestio/definition-mapper/libmapper/providers/aws/definition"
+	"github.com/stretchr/testify/suite"
+)
+
+// MapperTestSuite : Test suite for the aws mapper
+type MapperTestSuite struct {
+	suite.Suite
+	Mapper Mapper
+}
+
+// TestValidateDefinition : Testing every mapping error is reported
+func (suite *MapperTestSuite) TestValidateDefinition() {
+	d := def.New()
+	d.Name = "service"
+	d.IamRoles = []def.IamRole{{Name: "role"}, {Name: "role"}}
+	d.IamPolicies = []def.IamPolicy{{Name: "policy"}, {Name: "policy"}}
+
+	var duplicates int
+	for _, err := range suite.Mapper.ValidateDefinition(d) {
+		if err.Code == libmapper.ErrCodeInvalidDefinition {
+			duplicates++
+		}
+	}
+
+	suite.Equal(2, duplicates)
+}
+
+// TestMapperTestSuite : Test suite for the aws mapper
+func TestMapperTestSuite(t *testing.T) {
+	suite.Run(t, new(MapperTestSuite))
+}
@@ -0,0 +1,37 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	def "github.com/ernestio/definition-mapper/libmapper/providers/aws/definition"
+)
+
+// DefinitionSchema : returns a json schema describing the aws definition format
+func (m Mapper) DefinitionSchema() map[string]interface{} {
+	// definitions can set the any protocol by its definition value or as validated
+	protocols := []string{
+		components.PROTOCOLTCP,
+		components.PROTOCOLUDP,
+		components.PROTOCOLICMP,
+		MapDefinitionProtocol(components.PROTOCOLANY),
+		components.PROTOCOLANY,
+	}
+
+	enums := map[string][]string{
+		"security_groups.ingress.protocol": protocols,
+		"security_groups.egress.protocol":  protocols,
+		"loadbalancers.listeners.protocol": libmapper.CaseInsensitive(components.ELBPROTOCOLS),
+		"route53_zones.records.type":       components.DNSTYPES,
+		"s3_buckets.acl":                   components.S3ACLTYPES,
+		"s3_buckets.grantees.type":         components.S3GRANTEETYPES,
+		"s3_buckets.grantees.permissions":  components.S3PERMISSIONTYPES,
+		"rds_instances.storage.type":       components.StorageTypes,
+		"rds_instances.license":            components.Licenses,
+	}
+
+	return libmapper.Schema(def.New(), enums)
+}
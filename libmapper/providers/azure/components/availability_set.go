@@ -6,7 +6,6 @@ package components
 
 import (
 	"github.com/ernestio/ernestprovider/types/azure/availabilityset"
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -115,8 +114,7 @@ func (i *AvailabilitySet) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *AvailabilitySet) Validate() error {
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
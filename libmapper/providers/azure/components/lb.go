@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/lb"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -150,9 +147,7 @@ func (i *LB) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *LB) Validate() error {
-	log.Println("Validating LB")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
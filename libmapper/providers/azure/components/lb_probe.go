@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/lbprobe"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -127,9 +124,7 @@ func (i *LBProbe) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *LBProbe) Validate() error {
-	log.Println("Validating LB")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
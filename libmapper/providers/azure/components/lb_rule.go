@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/lbrule"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -161,9 +158,7 @@ func (i *LBRule) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *LBRule) Validate() error {
-	log.Println("Validating LB")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/networkinterface"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -188,9 +185,7 @@ func (i *NetworkInterface) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *NetworkInterface) Validate() error {
-	log.Println("Validating azure network interfaces")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
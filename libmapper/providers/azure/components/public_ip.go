@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/publicip"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -119,9 +116,7 @@ func (i *PublicIP) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *PublicIP) Validate() error {
-	log.Println("Validating public IP")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -23,7 +23,7 @@ type Query struct {
 	DatacenterName    string            `json:"datacenter_name,omitempty" diff:"-"`
 	DatacenterRegion  string            `json:"datacenter_region" diff:"-"`
 	ClientID          string            `json:"azure_client_id" diff:"-"`
-	ClientSecret      string            `json:"azure_client_secret" diff:"-"`
+	ClientSecret      string            `json:"azure_client_secret" diff:"-" sensitive:"true"`
 	TenantID          string            `json:"azure_tenant_id" diff:"-"`
 	SubscriptionID    string            `json:"azure_subscription_id" diff:"-"`
 	Environment       string            `json:"environment" diff:"-"`
@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/resourcegroup"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -117,10 +114,8 @@ func (i *ResourceGroup) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *ResourceGroup) Validate() error {
-	log.Println("Validating resource groups")
 
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/securitygroup"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -116,9 +113,7 @@ func (i *SecurityGroup) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *SecurityGroup) Validate() error {
-	log.Println("Validating security groups")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/sqldatabase"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -167,9 +164,7 @@ func (i *SQLDatabase) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *SQLDatabase) Validate() error {
-	log.Println("Validating SQL databases")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
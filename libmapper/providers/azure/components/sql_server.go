@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/sqlserver"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -116,9 +113,7 @@ func (i *SQLServer) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *SQLServer) Validate() error {
-	log.Println("Validating SQL Servers")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
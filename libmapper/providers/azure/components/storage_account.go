@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/storageaccount"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -131,9 +128,7 @@ func (i *StorageAccount) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *StorageAccount) Validate() error {
-	log.Println("Validating storage accounts")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/storagecontainer"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -112,9 +109,7 @@ func (i *StorageContainer) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *StorageContainer) Validate() error {
-	log.Println("Validating storage containers")
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/subnet"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -133,9 +130,7 @@ func (s *Subnet) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (s *Subnet) Validate() error {
-	log.Println("Validating subnets")
-	val := validator.NewValidator()
-	return val.Validate(s)
+	return validate(s)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -0,0 +1,62 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package components
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/ernestprovider/validator"
+)
+
+// validate : validates a component with the ernestprovider validator,
+// returning its first failure as a typed error on the field it names.
+// Failures are reported one per line, i.e. 'Name is a required field'
+func validate(c interface{}) error {
+	err := validator.NewValidator().Validate(c)
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.TrimSpace(strings.Split(strings.TrimSpace(err.Error()), "\n")[0])
+
+	code := libmapper.ErrCodeInvalidValue
+	if strings.Contains(msg, "required") {
+		code = libmapper.ErrCodeRequired
+	}
+
+	return libmapper.NewFieldError(code, jsonField(reflect.TypeOf(c), strings.SplitN(msg, " ", 2)[0]), msg)
+}
+
+// jsonField : returns the json key of the struct field with the given name or
+// key, searching embedded structs. Unknown fields return an empty key
+func jsonField(t reflect.Type, name string) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := strings.Split(f.Tag.Get("json"), ",")[0]
+
+		if f.Anonymous {
+			if k := jsonField(f.Type, name); k != "" {
+				return k
+			}
+			continue
+		}
+
+		if key != "" && key != "-" && (f.Name == name || key == name) {
+			return key
+		}
+	}
+
+	return ""
+}
@@ -7,7 +7,6 @@ package components
 import (
 	"strings"
 
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/virtualmachine"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -173,8 +172,7 @@ func (i *VirtualMachine) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (i *VirtualMachine) Validate() error {
-	val := validator.NewValidator()
-	return val.Validate(i)
+	return validate(i)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
@@ -5,9 +5,6 @@
 package components
 
 import (
-	"log"
-
-	"github.com/ernestio/ernestprovider/validator"
 	"github.com/ernestio/ernestprovider/types/azure/virtualnetwork"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
@@ -146,9 +143,7 @@ func (vn *VirtualNetwork) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (vn *VirtualNetwork) Validate() error {
-	log.Println("Validating Virtual network")
-	val := validator.NewValidator()
-	return val.Validate(vn)
+	return validate(vn)
 }
 
 // IsStateful : returns true if the component needs to be actioned to be removed.
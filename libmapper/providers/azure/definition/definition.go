@@ -22,6 +22,16 @@ func New() *Definition {
 	return &Definition{}
 }
 
+// SetName sets the name of the service
+func (d *Definition) SetName(name string) {
+	d.Name = name
+}
+
+// SetProject sets the project of the service
+func (d *Definition) SetProject(project string) {
+	d.Project = project
+}
+
 // LoadJSON unmarshals raw json data onto the defintion
 func (d *Definition) LoadJSON(data []byte) error {
 	return json.Unmarshal(data, d)
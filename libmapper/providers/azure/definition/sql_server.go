@@ -10,7 +10,7 @@ type SQLServer struct {
 	Name                       string            `json:"name,omitempty" yaml:"name,omitempty"`
 	Version                    string            `json:"version,omitempty" yaml:"version,omitempty"`
 	AdministratorLogin         string            `json:"administrator_login,omitempty" yaml:"administrator_login,omitempty"`
-	AdministratorLoginPassword string            `json:"administrator_login_password,omitempty" yaml:"administrator_login_password,omitempty"`
+	AdministratorLoginPassword string            `json:"administrator_login_password,omitempty" yaml:"administrator_login_password,omitempty" sensitive:"true"`
 	Tags                       map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
 	Databases                  []SQLDatabase     `json:"databases,omitempty" yaml:"databases,omitempty"`
 	FirewallRules              []SQLFirewallRule `json:"rules,omitempty" yaml:"rules,omitempty"`
@@ -46,7 +46,7 @@ type VirtualMachine struct {
 // Authentication ...
 type Authentication struct {
 	AdminUsername                 string            `json:"admin_username,omitempty" yaml:"admin_username,omitempty"`
-	AdminPassword                 string            `json:"admin_password,omitempty" yaml:"admin_password,omitempty"`
+	AdminPassword                 string            `json:"admin_password,omitempty" yaml:"admin_password,omitempty" sensitive:"true"`
 	SSHKeys                       map[string]string `json:"ssh_keys,omitempty" yaml:"ssh_keys,omitempty"`
 	DisablePasswordAuthentication *bool             `json:"disable_password_authentication,omitempty" yaml:"disable_password_authentication,omitempty"`
 }
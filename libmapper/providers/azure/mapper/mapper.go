@@ -5,12 +5,12 @@
 package mapper
 
 import (
-	"errors"
-	"log"
+	"fmt"
 
 	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/ernestio/definition-mapper/libmapper/providers/azure/components"
 	def "github.com/ernestio/definition-mapper/libmapper/providers/azure/definition"
+	"github.com/ernestio/definition-mapper/logger"
 	"github.com/mitchellh/mapstructure"
 	"github.com/r3labs/graph"
 )
@@ -19,20 +19,35 @@ import (
 var SUPPORTEDCOMPONENTS = []string{"network_interface", "public_ip", "resource_group", "security_group", "sql_firewall_rule", "sql_database", "sql_server", "storage_account", "storage_container", "subnet", "virtual_machine", "virtual_network", "lb", "availability_set", "lb_backend_address_pool", "lb_rule", "lb_probe"}
 
 // Mapper : implements the generic mapper structure
-type Mapper struct{}
+type Mapper struct {
+	logger *logger.Logger
+}
 
 // New : returns a new azure mapper
 func New() libmapper.Mapper {
 	return &Mapper{}
 }
 
+// SetLogger : sets the logger the mapper writes to, so lines can be tied to a request
+func (m *Mapper) SetLogger(l *logger.Logger) {
+	m.logger = l
+}
+
+func (m Mapper) log() *logger.Logger {
+	if m.logger == nil {
+		return logger.Default
+	}
+
+	return m.logger
+}
+
 // ConvertDefinition : converts the input yaml definition to a graph format
 func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error) {
 	g := graph.New()
 
 	d, ok := gd.(*def.Definition)
 	if ok != true {
-		return g, errors.New("Could not convert generic definition into azure format")
+		return g, libmapper.NewError(libmapper.ErrCodeInvalidDefinition, "Could not convert generic definition into azure format")
 	}
 
 	// Map basic component values from definition
@@ -45,15 +60,17 @@ func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error)
 		c.Rebuild(g)
 
 		// Validate Components
+		m.log().Debug("validating component", logger.Fields{"component": c.GetID()})
+
 		err := c.Validate()
 		if err != nil {
-			return g, err
+			return g, libmapper.ComponentError(c, err)
 		}
 
 		// Build internal & template values
 		for _, dep := range c.Dependencies() {
 			if g.HasComponent(dep) != true {
-				return g, errors.New("Component '" + c.GetID() + "': Could not resolve component dependency '" + dep + "'")
+				return g, libmapper.DependencyError(c, dep)
 			}
 		}
 
@@ -66,6 +83,22 @@ func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error)
 	return g, nil
 }
 
+// ValidateDefinition : validates every component of the input definition, collecting all errors found
+func (m Mapper) ValidateDefinition(gd libmapper.Definition) libmapper.Errors {
+	g := graph.New()
+
+	d, ok := gd.(*def.Definition)
+	if ok != true {
+		return libmapper.Errors{libmapper.NewError(libmapper.ErrCodeInvalidDefinition, "Could not convert generic definition into azure format")}
+	}
+
+	var errs libmapper.Errors
+
+	errs = errs.Append(mapComponents(d, g), libmapper.ErrCodeInvalidDefinition)
+
+	return append(errs, libmapper.ValidateGraph(g)...)
+}
+
 // ConvertGraph : converts the service graph into an input yaml format
 func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 	var d def.Definition
@@ -82,13 +115,13 @@ func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 
 		for _, dep := range c.Dependencies() {
 			if g.HasComponent(dep) != true {
-				return g, errors.New("Component '" + c.GetID() + "': Could not resolve component dependency '" + dep + "'")
+				return g, libmapper.DependencyError(c, dep)
 			}
 		}
 
 		err := c.Validate()
 		if err != nil {
-			return d, err
+			return d, libmapper.ComponentError(c, err)
 		}
 	}
 
@@ -109,6 +142,9 @@ func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 		d.ResourceGroups[i].AvailabilitySets = MapDefinitionAvailabilitySets(g, &d.ResourceGroups[i])
 	}
 
+	// secret values resolved when the definition was mapped are returned as their references
+	libmapper.RestoreSecrets(&d, libmapper.GraphSecretReferences(g))
+
 	return &d, nil
 }
 
@@ -121,6 +157,17 @@ func (m Mapper) LoadDefinition(gd map[string]interface{}) (libmapper.Definition,
 	return &d, err
 }
 
+// UnknownDefinitionKeys : returns an error for every key of the input definition that doesn't match a definition field
+func (m Mapper) UnknownDefinitionKeys(gd map[string]interface{}) libmapper.Errors {
+	return libmapper.UnknownKeys(def.New(), gd)
+}
+
+// ComponentTypes : returns all component types definitions are mapped to. Managed
+// disks are mapped from virtual machines, but are not imported on their own
+func (m Mapper) ComponentTypes() []string {
+	return append(append([]string{}, SUPPORTEDCOMPONENTS...), components.TYPEMANAGEDDISK)
+}
+
 // LoadGraph : returns a generic interal graph
 func (m Mapper) LoadGraph(gg map[string]interface{}) (*graph.Graph, error) {
 	g := graph.New()
@@ -211,7 +258,7 @@ func (m Mapper) CreateImportGraph(params []string) *graph.Graph {
 		for x := 0; x < len(params); x++ {
 			q := MapQuery(ctype+"s", params[x])
 			if err := g.AddComponent(q); err != nil {
-				log.Println(err.Error())
+				m.log().Error("could not add import query", logger.Fields{"error": err})
 			}
 		}
 	}
@@ -219,6 +266,33 @@ func (m Mapper) CreateImportGraph(params []string) *graph.Graph {
 	return g
 }
 
+// ImportFilters : returns the filters an import is queried with, azure imports
+// are filtered by the resource group names set as filters
+func (m Mapper) ImportFilters(filters []string, service string) []string {
+	return filters
+}
+
+// GroupComponents : groups imported virtual machines that were not created by ernest.
+// Managed disks are not grouped, as they are not imported on their own, but
+// mapped from the disks of the virtual machines they are attached to
+func (m Mapper) GroupComponents(g *graph.Graph) libmapper.Errors {
+	return libmapper.GroupComponents(g, components.TYPEVIRTUALMACHINE, libmapper.Grouping{
+		Tag: components.GROUPINSTANCE,
+		Signature: func(c graph.Component) string {
+			vm := c.(*components.VirtualMachine)
+			image := vm.StorageImageReference
+			return fmt.Sprint(vm.ResourceGroupName, vm.VMSize, vm.AvailabilitySet, vm.LicenseType, image.Publisher, image.Offer, image.Sku, image.Version)
+		},
+		SetGroup: func(c graph.Component, group string) {
+			vm := c.(*components.VirtualMachine)
+			if vm.Tags == nil {
+				vm.Tags = make(map[string]string)
+			}
+			vm.Tags[components.GROUPINSTANCE] = group
+		},
+	})
+}
+
 // ProviderCredentials : maps aws credentials to a generic component
 func (m Mapper) ProviderCredentials(details map[string]interface{}) graph.Component {
 	credentials := make(graph.GenericComponent)
@@ -240,28 +314,22 @@ func (m Mapper) ProviderCredentials(details map[string]interface{}) graph.Compon
 
 // mapComponents : Map basic component values from definition
 func mapComponents(d *def.Definition, g *graph.Graph) error {
+	var errs libmapper.Errors
+
 	for _, rg := range MapResourceGroups(d) {
-		if err := g.AddComponent(rg); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(rg), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, vn := range MapVirtualNetworks(d) {
-		if err := g.AddComponent(vn); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(vn), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, vm := range MapVirtualMachines(d) {
-		if err := g.AddComponent(vm); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(vm), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, subnet := range MapSubnets(d) {
-		if err := g.AddComponent(subnet); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(subnet), libmapper.ErrCodeInvalidDefinition)
 	}
 	/*
 		for _, ni := range MapManagedDisks(d) {
@@ -271,84 +339,58 @@ func mapComponents(d *def.Definition, g *graph.Graph) error {
 		}
 	*/
 	for _, ni := range MapNetworkInterfaces(d) {
-		if err := g.AddComponent(ni); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(ni), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, ip := range MapPublicIPs(d) {
-		if err := g.AddComponent(ip); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(ip), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, lb := range MapLBs(d) {
-		if err := g.AddComponent(lb); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(lb), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, rule := range MapLBRules(d) {
-		if err := g.AddComponent(rule); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(rule), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, probe := range MapLBProbes(d) {
-		if err := g.AddComponent(probe); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(probe), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, ap := range MapLBBackendAddressPools(d) {
-		if err := g.AddComponent(ap); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(ap), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, sg := range MapSecurityGroups(d) {
-		if err := g.AddComponent(sg); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(sg), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, ss := range MapSQLServers(d) {
-		if err := g.AddComponent(ss); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(ss), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, sd := range MapSQLDatabases(d) {
-		if err := g.AddComponent(sd); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(sd), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, sd := range MapSQLFirewallRules(d) {
-		if err := g.AddComponent(sd); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(sd), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, sa := range MapStorageAccounts(d) {
-		if err := g.AddComponent(sa); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(sa), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, sc := range MapStorageContainers(d) {
-		if err := g.AddComponent(sc); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(sc), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, as := range MapAvailabilitySets(d) {
-		if err := g.AddComponent(as); err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(as), libmapper.ErrCodeInvalidDefinition)
 	}
 
-	return nil
+	return errs.Err()
 }
 
 func mapTags(name, service string) map[string]string {
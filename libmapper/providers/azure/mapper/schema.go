@@ -0,0 +1,111 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"github.com/ernestio/definition-mapper/libmapper"
+	def "github.com/ernestio/definition-mapper/libmapper/providers/azure/definition"
+)
+
+var (
+	// VMSIZES : virtual machine sizes of the general purpose, compute, memory,
+	// storage and gpu families
+	VMSIZES = []string{
+		"Basic_A0", "Basic_A1", "Basic_A2", "Basic_A3", "Basic_A4",
+		"Standard_A0", "Standard_A1", "Standard_A2", "Standard_A3", "Standard_A4", "Standard_A5", "Standard_A6", "Standard_A7", "Standard_A8", "Standard_A9", "Standard_A10", "Standard_A11",
+		"Standard_A1_v2", "Standard_A2_v2", "Standard_A4_v2", "Standard_A8_v2", "Standard_A2m_v2", "Standard_A4m_v2", "Standard_A8m_v2",
+		"Standard_B1ls", "Standard_B1s", "Standard_B1ms", "Standard_B2s", "Standard_B2ms", "Standard_B4ms", "Standard_B8ms", "Standard_B12ms", "Standard_B16ms", "Standard_B20ms",
+		"Standard_D1", "Standard_D2", "Standard_D3", "Standard_D4", "Standard_D11", "Standard_D12", "Standard_D13", "Standard_D14",
+		"Standard_D1_v2", "Standard_D2_v2", "Standard_D3_v2", "Standard_D4_v2", "Standard_D5_v2", "Standard_D11_v2", "Standard_D12_v2", "Standard_D13_v2", "Standard_D14_v2", "Standard_D15_v2",
+		"Standard_DS1", "Standard_DS2", "Standard_DS3", "Standard_DS4", "Standard_DS11", "Standard_DS12", "Standard_DS13", "Standard_DS14",
+		"Standard_DS1_v2", "Standard_DS2_v2", "Standard_DS3_v2", "Standard_DS4_v2", "Standard_DS5_v2", "Standard_DS11_v2", "Standard_DS12_v2", "Standard_DS13_v2", "Standard_DS14_v2", "Standard_DS15_v2",
+		"Standard_D2_v3", "Standard_D4_v3", "Standard_D8_v3", "Standard_D16_v3", "Standard_D32_v3", "Standard_D48_v3", "Standard_D64_v3",
+		"Standard_D2s_v3", "Standard_D4s_v3", "Standard_D8s_v3", "Standard_D16s_v3", "Standard_D32s_v3", "Standard_D48s_v3", "Standard_D64s_v3",
+		"Standard_E2_v3", "Standard_E4_v3", "Standard_E8_v3", "Standard_E16_v3", "Standard_E20_v3", "Standard_E32_v3", "Standard_E48_v3", "Standard_E64_v3",
+		"Standard_E2s_v3", "Standard_E4s_v3", "Standard_E8s_v3", "Standard_E16s_v3", "Standard_E20s_v3", "Standard_E32s_v3", "Standard_E48s_v3", "Standard_E64s_v3",
+		"Standard_F1", "Standard_F2", "Standard_F4", "Standard_F8", "Standard_F16",
+		"Standard_F1s", "Standard_F2s", "Standard_F4s", "Standard_F8s", "Standard_F16s",
+		"Standard_F2s_v2", "Standard_F4s_v2", "Standard_F8s_v2", "Standard_F16s_v2", "Standard_F32s_v2", "Standard_F48s_v2", "Standard_F64s_v2", "Standard_F72s_v2",
+		"Standard_G1", "Standard_G2", "Standard_G3", "Standard_G4", "Standard_G5",
+		"Standard_GS1", "Standard_GS2", "Standard_GS3", "Standard_GS4", "Standard_GS5",
+		"Standard_L4s", "Standard_L8s", "Standard_L16s", "Standard_L32s",
+		"Standard_M64s", "Standard_M64ms", "Standard_M128s", "Standard_M128ms",
+		"Standard_NC6", "Standard_NC12", "Standard_NC24", "Standard_NC24r",
+		"Standard_NV6", "Standard_NV12", "Standard_NV24",
+		"Standard_H8", "Standard_H16", "Standard_H8m", "Standard_H16m", "Standard_H16r", "Standard_H16mr",
+	}
+	// DISKTYPES : storage types of managed disks
+	DISKTYPES = []string{"Standard_LRS", "StandardSSD_LRS", "Premium_LRS", "UltraSSD_LRS"}
+	// DISKCACHING : caching modes of virtual machine disks
+	DISKCACHING = []string{"None", "ReadOnly", "ReadWrite"}
+	// DISKCREATEOPTIONS : the ways virtual machine disks can be created
+	DISKCREATEOPTIONS = []string{"FromImage", "Empty", "Attach"}
+	// OSTYPES : operating systems of virtual machine disks
+	OSTYPES = []string{"Linux", "Windows"}
+	// LICENSETYPES : windows licenses virtual machines can be brought with
+	LICENSETYPES = []string{"Windows_Client", "Windows_Server"}
+	// ALLOCATIONMETHODS : the ways ip addresses are allocated
+	ALLOCATIONMETHODS = []string{"Static", "Dynamic"}
+	// SECURITYRULEPROTOCOLS : protocols of security group rules
+	SECURITYRULEPROTOCOLS = []string{"Tcp", "Udp", "*"}
+	// SECURITYRULEDIRECTIONS : directions of security group rules
+	SECURITYRULEDIRECTIONS = []string{"Inbound", "Outbound"}
+	// SECURITYRULEACCESSES : whether security group rules allow or deny traffic
+	SECURITYRULEACCESSES = []string{"Allow", "Deny"}
+	// LBRULEPROTOCOLS : protocols of load balancer rules
+	LBRULEPROTOCOLS = []string{"Tcp", "Udp", "All"}
+	// LBPROBEPROTOCOLS : protocols of load balancer probes
+	LBPROBEPROTOCOLS = []string{"Tcp", "Http", "Https"}
+	// LBLOADDISTRIBUTIONS : the ways load balancer rules distribute traffic
+	LBLOADDISTRIBUTIONS = []string{"Default", "SourceIP", "SourceIPProtocol"}
+	// STORAGEACCOUNTTYPES : tiers and replication types of storage accounts
+	STORAGEACCOUNTTYPES = []string{"Standard_LRS", "Standard_GRS", "Standard_RAGRS", "Standard_ZRS", "Premium_LRS"}
+	// STORAGEACCOUNTKINDS : kinds of storage accounts
+	STORAGEACCOUNTKINDS = []string{"Storage", "StorageV2", "BlobStorage"}
+	// CONTAINERACCESSTYPES : access levels of storage containers
+	CONTAINERACCESSTYPES = []string{"private", "blob", "container"}
+	// SQLVERSIONS : versions of sql servers
+	SQLVERSIONS = []string{"2.0", "12.0"}
+	// SQLEDITIONS : editions of sql databases
+	SQLEDITIONS = []string{"Basic", "Standard", "Premium", "DataWarehouse", "Business", "BusinessCritical", "Free", "GeneralPurpose", "Hyperscale", "Stretch", "System", "System2", "Web"}
+	// SQLCREATEMODES : the ways sql databases can be created
+	SQLCREATEMODES = []string{"Default", "Copy", "OnlineSecondary", "NonReadableSecondary", "PointInTimeRestore", "Recovery", "Restore", "RestoreLongTermRetentionBackup"}
+)
+
+// DefinitionSchema : returns a json schema describing the azure definition format
+func (m Mapper) DefinitionSchema() map[string]interface{} {
+	allocations := libmapper.CaseInsensitive(ALLOCATIONMETHODS)
+
+	enums := map[string][]string{
+		"resource_groups.virtual_machines.size":                                                               VMSIZES,
+		"resource_groups.virtual_machines.license_type":                                                       LICENSETYPES,
+		"resource_groups.virtual_machines.storage_os_disk.managed_disk_type":                                  DISKTYPES,
+		"resource_groups.virtual_machines.storage_os_disk.caching":                                            libmapper.CaseInsensitive(DISKCACHING),
+		"resource_groups.virtual_machines.storage_os_disk.create_option":                                      libmapper.CaseInsensitive(DISKCREATEOPTIONS),
+		"resource_groups.virtual_machines.storage_os_disk.os_type":                                            OSTYPES,
+		"resource_groups.virtual_machines.storage_data_disk.managed_disk_type":                                DISKTYPES,
+		"resource_groups.virtual_machines.storage_data_disk.caching":                                          libmapper.CaseInsensitive(DISKCACHING),
+		"resource_groups.virtual_machines.storage_data_disk.create_option":                                    libmapper.CaseInsensitive(DISKCREATEOPTIONS),
+		"resource_groups.virtual_machines.storage_data_disk.os_type":                                          OSTYPES,
+		"resource_groups.virtual_machines.network_interfaces.ip_configurations.public_ip_address_allocation":  allocations,
+		"resource_groups.virtual_machines.network_interfaces.ip_configurations.private_ip_address_allocation": allocations,
+		"resource_groups.loadbalancers.frontend_ip_configurations.public_ip_address_allocation":               allocations,
+		"resource_groups.loadbalancers.frontend_ip_configurations.private_ip_address_allocation":              allocations,
+		"resource_groups.loadbalancers.frontend_ip_configurations.rules.protocol":                             libmapper.CaseInsensitive(LBRULEPROTOCOLS),
+		"resource_groups.loadbalancers.frontend_ip_configurations.rules.load_distribution":                    LBLOADDISTRIBUTIONS,
+		"resource_groups.loadbalancers.probes.protocol":                                                       libmapper.CaseInsensitive(LBPROBEPROTOCOLS),
+		"resource_groups.security_groups.rules.protocol":                                                      libmapper.CaseInsensitive(SECURITYRULEPROTOCOLS),
+		"resource_groups.security_groups.rules.direction":                                                     libmapper.CaseInsensitive(SECURITYRULEDIRECTIONS),
+		"resource_groups.security_groups.rules.access":                                                        libmapper.CaseInsensitive(SECURITYRULEACCESSES),
+		"resource_groups.storage_accounts.account_type":                                                       STORAGEACCOUNTTYPES,
+		"resource_groups.storage_accounts.account_kind":                                                       STORAGEACCOUNTKINDS,
+		"resource_groups.storage_accounts.containers.access_type":                                             CONTAINERACCESSTYPES,
+		"resource_groups.sql_servers.version":                                                                 SQLVERSIONS,
+		"resource_groups.sql_servers.databases.edition":                                                       SQLEDITIONS,
+		"resource_groups.sql_servers.databases.create_mode":                                                   SQLCREATEMODES,
+	}
+
+	return libmapper.Schema(def.New(), enums)
+}
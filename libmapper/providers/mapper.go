@@ -11,16 +11,18 @@ import (
 	vcloud "github.com/ernestio/definition-mapper/libmapper/providers/vcloud/mapper"
 )
 
+func init() {
+	Register("aws", func() libmapper.Mapper { return aws.New() }, "aws-fake")
+	Register("vcloud", func() libmapper.Mapper { return vcloud.New() }, "vcloud-fake")
+	Register("azure", func() libmapper.Mapper { return azure.New() }, "azure-fake")
+}
+
 // NewMapper : Get a new mapper based on a specified type
-func NewMapper(t string) (m libmapper.Mapper) {
-	switch t {
-	case "aws", "aws-fake":
-		m = aws.New()
-	case "vcloud", "vcloud-fake":
-		m = vcloud.New()
-	case "azure", "azure-fake":
-		m = azure.New()
+func NewMapper(t string) (libmapper.Mapper, error) {
+	f, ok := lookup(t)
+	if !ok {
+		return nil, unsupported(t)
 	}
 
-	return m
+	return f(), nil
 }
@@ -0,0 +1,122 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package providers
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+)
+
+// Factory : returns a new mapper for a provider
+type Factory func() libmapper.Mapper
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+	aliases   = make(map[string]string)
+)
+
+// Register : registers a provider's mapper under its name, along with any
+// aliases it can also be requested as. Providers declared outside of this
+// package can register themselves from an init function. Registering a
+// name or alias that is already in use replaces it
+func Register(name string, f Factory, alias ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	factories[name] = f
+	delete(aliases, name)
+
+	for _, a := range alias {
+		aliases[a] = name
+	}
+}
+
+// Unregister : removes a provider's mapper and its aliases from the registry
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(factories, name)
+
+	for a, n := range aliases {
+		if n == name {
+			delete(aliases, a)
+		}
+	}
+}
+
+// Providers : returns the names of all registered providers
+func Providers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Aliases : returns all aliases registered for a provider
+func Aliases(name string) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var names []string
+	for a, n := range aliases {
+		if n == name {
+			names = append(names, a)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Name : returns the name of the provider registered under a name or alias
+func Name(t string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	name, _, ok := resolve(t)
+
+	return name, ok
+}
+
+func lookup(t string) (Factory, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_, f, ok := resolve(t)
+
+	return f, ok
+}
+
+// resolve : returns the name and factory of a provider, the registry must be locked by the caller
+func resolve(t string) (string, Factory, bool) {
+	if name, ok := aliases[t]; ok {
+		t = name
+	}
+
+	f, ok := factories[t]
+
+	return t, f, ok
+}
+
+func unsupported(t string) error {
+	if t == "" {
+		return libmapper.NewError(libmapper.ErrCodeUnsupportedProvider, "could not infer environment provider type")
+	}
+
+	return libmapper.NewError(libmapper.ErrCodeUnsupportedProvider, "unsupported provider type: '"+t+"', must be one of "+strings.Join(Providers(), ", "))
+}
@@ -0,0 +1,83 @@
+package providers_test
+
+// Basic imports
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers"
+	aws "github.com/ernestio/definition-mapper/libmapper/providers/aws/mapper"
+	"github.com/stretchr/testify/suite"
+)
+
+// RegistryTestSuite : Test suite for the provider registry
+type RegistryTestSuite struct {
+	suite.Suite
+}
+
+// TearDownTest : Removes providers registered by tests
+func (suite *RegistryTestSuite) TearDownTest() {
+	providers.Unregister("in-house")
+}
+
+// TestBuiltin : Testing builtin providers are registered with their aliases
+func (suite *RegistryTestSuite) TestBuiltin() {
+	for _, p := range []string{"aws", "aws-fake", "azure", "azure-fake", "vcloud", "vcloud-fake"} {
+		m, err := providers.NewMapper(p)
+		suite.Nil(err)
+		suite.NotNil(m)
+	}
+
+	name, ok := providers.Name("aws-fake")
+	suite.True(ok)
+	suite.Equal("aws", name)
+}
+
+// TestRegister : Testing providers can be registered
+func (suite *RegistryTestSuite) TestRegister() {
+	providers.Register("in-house", func() libmapper.Mapper { return aws.New() }, "in-house-fake")
+
+	m, err := providers.NewMapper("in-house-fake")
+	suite.Nil(err)
+	suite.NotNil(m)
+	suite.Contains(providers.Providers(), "in-house")
+
+	providers.Unregister("in-house")
+	suite.NotContains(providers.Providers(), "in-house")
+
+	_, ok := providers.Name("in-house-fake")
+	suite.False(ok)
+}
+
+// TestCoreMapper : Testing mappers implementing only the core interface fall back to defaults
+func (suite *RegistryTestSuite) TestCoreMapper() {
+	providers.Register("in-house", func() libmapper.Mapper { return coreMapper{aws.New()} })
+
+	m, err := providers.NewMapper("in-house")
+	suite.Nil(err)
+
+	_, ok := m.(libmapper.SchemaMapper)
+	suite.False(ok)
+	suite.Equal(libmapper.SCHEMAVERSION, libmapper.DefinitionSchema(m)["$schema"])
+	suite.Equal([]string{}, libmapper.ComponentTypes(m))
+	suite.Nil(libmapper.UnknownDefinitionKeys(m, map[string]interface{}{"unknown": true}))
+}
+
+// TestUnsupported : Testing unknown providers return an error
+func (suite *RegistryTestSuite) TestUnsupported() {
+	m, err := providers.NewMapper("unknown")
+	suite.Nil(m)
+	suite.NotNil(err)
+	suite.Equal(libmapper.ErrCodeUnsupportedProvider, err.(libmapper.Error).Code)
+	suite.Contains(err.Error(), "must be one of")
+}
+
+// TestRegistryTestSuite : Test suite for the provider registry
+func TestRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
+
+// coreMapper : hides the optional methods of a mapper
+type coreMapper struct {
+	libmapper.Mapper
+}
@@ -9,6 +9,6 @@ type Credentials struct {
 	Type      string `json:"type"`
 	Vdc       string `json:"vcloud_vdc_name"`
 	Username  string `json:"vcloud_username"`
-	Password  string `json:"vcloud_password"`
+	Password  string `json:"vcloud_password" sensitive:"true"`
 	VCloudURL string `json:"vcloud_url"`
 }
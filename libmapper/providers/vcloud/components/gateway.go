@@ -5,8 +5,9 @@
 package components
 
 import (
-	"errors"
+	"strconv"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -137,71 +138,71 @@ func (gw *Gateway) SequentialDependencies() []string {
 
 // Validate : validates the components values
 func (gw *Gateway) Validate() error {
-	for _, rule := range gw.FirewallRules {
+	for i, rule := range gw.FirewallRules {
 		// Check if firewall rule name is null
 		if rule.Name == "" {
-			return errors.New("Firewall Rule name should not be null")
+			return libmapper.NewFieldError(libmapper.ErrCodeRequired, "firewall_rules["+strconv.Itoa(i)+"].name", "Firewall Rule name should not be null")
 		}
 
 		err := validateIP(rule.SourceIP, "Firewall Rule Source")
 		if err != nil {
-			return err
+			return withField(err, "firewall_rules["+strconv.Itoa(i)+"].source_ip", "")
 		}
 
 		err = validateIP(rule.DestinationIP, "Firewall Rule Destination")
 		if err != nil {
-			return err
+			return withField(err, "firewall_rules["+strconv.Itoa(i)+"].destination_ip", "")
 		}
 
 		// Validate FromPort Port
 		// Must be: [any | 1 - 65535]
 		err = validatePort(rule.SourcePort, "Firewall Rule From")
 		if err != nil {
-			return err
+			return withField(err, "firewall_rules["+strconv.Itoa(i)+"].source_port", "")
 		}
 
 		// Validate ToPort Port
 		// Must be: [any | 1 - 65535]
 		err = validatePort(rule.DestinationPort, "Firewall Rule To")
 		if err != nil {
-			return err
+			return withField(err, "firewall_rules["+strconv.Itoa(i)+"].destination_port", "")
 		}
 
 		// Validate Protocol
 		// Must be one of: tcp | udp | icmp | any | tcp & udp
 		err = validateProtocol(rule.Protocol)
 		if err != nil {
-			return err
+			return withField(err, "firewall_rules["+strconv.Itoa(i)+"].protocol", "")
 		}
 
 		// Validate Action
 		// Must be: allow | drop
 		err = validateAction(rule.Action)
 		if err != nil {
-			return err
+			return withField(err, "firewall_rules["+strconv.Itoa(i)+"].action", "")
 		}
 	}
 
-	for _, rule := range gw.NatRules {
+	for i, rule := range gw.NatRules {
 		// Check if Destination is a valid IP
 		err := validateIP(rule.OriginIP, "Nat Rule Source")
 		if err != nil {
-			return err
+			return withField(err, "nat_rules["+strconv.Itoa(i)+"].origin_ip", "")
 		}
 
 		err = validateIP(rule.TranslationIP, "Nat Rule Destination")
 		if err != nil {
-			return err
+			return withField(err, "nat_rules["+strconv.Itoa(i)+"].translation_ip", "")
 		}
 
 		err = validatePort(rule.OriginPort, "Port Forwarding From")
 		if err != nil {
-			return err
+			return withField(err, "nat_rules["+strconv.Itoa(i)+"].origin_port", "")
 		}
 
 		err = validatePort(rule.TranslationPort, "Port Forwarding To")
 		if err != nil {
-			return err
+			return withField(err, "nat_rules["+strconv.Itoa(i)+"].translation_port", "")
 		}
 	}
 
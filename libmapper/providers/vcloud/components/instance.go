@@ -5,8 +5,7 @@
 package components
 
 import (
-	"errors"
-
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -145,35 +144,35 @@ func (i *Instance) SequentialDependencies() []string {
 // Validate : validates the components values
 func (i *Instance) Validate() error {
 	if i.Name == "" {
-		return errors.New("Instance name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "Instance name should not be null")
 	}
 
 	if i.Image == "" {
-		return errors.New("Instance image should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "reference_image", "Instance image should not be null")
 	}
 
 	if i.Catalog == "" {
-		return errors.New("Instance image catalog should not be null, use format <catalog>/<image>")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "reference_catalog", "Instance image catalog should not be null, use format <catalog>/<image>")
 	}
 
 	if i.Image == "" {
-		return errors.New("Instance image image should not be null, use format <catalog>/<image>")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "reference_image", "Instance image image should not be null, use format <catalog>/<image>")
 	}
 
 	if i.Cpus < 1 {
-		return errors.New("Instance cpus should not be < 1")
+		return libmapper.NewFieldError(libmapper.ErrCodeOutOfRange, "cpus", "Instance cpus should not be < 1")
 	}
 
 	if i.Memory < 1 {
-		return errors.New("Instance memory should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "ram", "Instance memory should not be null")
 	}
 
 	if i.Network == "" {
-		return errors.New("Instance network name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "network", "Instance network name should not be null")
 	}
 
 	if i.IP == "" {
-		return errors.New("Instance network start_ip should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "ip", "Instance network start_ip should not be null")
 	}
 
 	return nil
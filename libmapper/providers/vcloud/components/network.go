@@ -5,9 +5,10 @@
 package components
 
 import (
-	"errors"
 	"net"
+	"strconv"
 
+	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/r3labs/diff"
 	"github.com/r3labs/graph"
 )
@@ -125,15 +126,15 @@ func (n *Network) SequentialDependencies() []string {
 // Validate : validates the components values
 func (n *Network) Validate() error {
 	if n.Name == "" {
-		return errors.New("Network name should not be null")
+		return libmapper.NewFieldError(libmapper.ErrCodeRequired, "name", "Network name should not be null")
 	}
 
-	for _, val := range n.DNS {
+	for i, val := range n.DNS {
 		if val == "" {
 			continue
 		}
 		if ok := net.ParseIP(val); ok == nil {
-			return errors.New("DNS " + val + " is not a valid CIDR")
+			return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "dns["+strconv.Itoa(i)+"]", "DNS "+val+" is not a valid CIDR")
 		}
 	}
 
@@ -143,7 +144,7 @@ func (n *Network) Validate() error {
 
 	_, _, err := net.ParseCIDR(n.Subnet)
 	if err != nil {
-		return errors.New("Network CIDR is not valid")
+		return libmapper.NewFieldError(libmapper.ErrCodeInvalidFormat, "range", "Network CIDR is not valid")
 	}
 
 	return nil
@@ -5,10 +5,11 @@
 package components
 
 import (
-	"errors"
 	"fmt"
 	"net"
 	"strconv"
+
+	"github.com/ernestio/definition-mapper/libmapper"
 )
 
 const (
@@ -28,7 +29,7 @@ func validateProtocol(p string) error {
 	case PROTOCOLTCP, PROTOCOLUDP, PROTOCOLICMP, PROTOCOLANY, PROTOCOLTCPUDP:
 		return nil
 	}
-	return errors.New("Protocol is invalid")
+	return libmapper.NewError(libmapper.ErrCodeInvalidValue, "Protocol is invalid")
 }
 
 // validateAction checks if a string is a valid action value.
@@ -37,7 +38,7 @@ func validateAction(s string) error {
 	case "allow", "drop":
 		return nil
 	}
-	return errors.New("Action is invalid")
+	return libmapper.NewError(libmapper.ErrCodeInvalidValue, "Action is invalid")
 }
 
 // ValidateIP checks if an string is a valid source/destionation
@@ -62,7 +63,7 @@ func validateIP(ip, iptype string) error {
 		return nil
 	}
 
-	return fmt.Errorf("%s (%s) is not valid", iptype, ip)
+	return libmapper.NewError(libmapper.ErrCodeInvalidFormat, fmt.Sprintf("%s (%s) is not valid", iptype, ip))
 }
 
 // ValidatePort checks an string to be a valid TCP port
@@ -73,12 +74,30 @@ func validatePort(p, ptype string) error {
 
 	port, err := strconv.Atoi(p)
 	if err != nil {
-		return fmt.Errorf("%s Port (%s) is not valid", ptype, p)
+		return libmapper.NewError(libmapper.ErrCodeInvalidFormat, fmt.Sprintf("%s Port (%s) is not valid", ptype, p))
 	}
 
 	if port < 1 || port > 65535 {
-		return fmt.Errorf("%s Port (%s) is out of range [1 - 65535]", ptype, p)
+		return libmapper.NewError(libmapper.ErrCodeOutOfRange, fmt.Sprintf("%s Port (%s) is out of range [1 - 65535]", ptype, p))
 	}
 
 	return nil
 }
+
+// withField : returns a validation error for a field, keeping the code of
+// typed errors. Fields already set on the error are nested under the field
+func withField(err error, field, prefix string) libmapper.Error {
+	e, ok := err.(libmapper.Error)
+	if !ok {
+		e = libmapper.NewError(libmapper.ErrCodeInvalidValue, err.Error())
+	}
+
+	if e.Field != "" {
+		field = field + "." + e.Field
+	}
+
+	e.Field = field
+	e.Message = prefix + e.Message
+
+	return e
+}
@@ -5,12 +5,13 @@
 package mapper
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/ernestio/definition-mapper/libmapper"
 	"github.com/ernestio/definition-mapper/libmapper/providers/vcloud/components"
 	def "github.com/ernestio/definition-mapper/libmapper/providers/vcloud/definition"
+	"github.com/ernestio/definition-mapper/logger"
 	"github.com/mitchellh/mapstructure"
 	"github.com/r3labs/graph"
 )
@@ -19,20 +20,35 @@ import (
 var SUPPORTEDCOMPONENTS = []string{"router", "network", "instance"}
 
 // Mapper : implements the generic mapper structure
-type Mapper struct{}
+type Mapper struct {
+	logger *logger.Logger
+}
 
 // New : returns a new aws mapper
 func New() libmapper.Mapper {
 	return &Mapper{}
 }
 
+// SetLogger : sets the logger the mapper writes to, so lines can be tied to a request
+func (m *Mapper) SetLogger(l *logger.Logger) {
+	m.logger = l
+}
+
+func (m Mapper) log() *logger.Logger {
+	if m.logger == nil {
+		return logger.Default
+	}
+
+	return m.logger
+}
+
 // ConvertDefinition : converts the input yaml definition to a graph format
 func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error) {
 	g := graph.New()
 
 	d, ok := gd.(*def.Definition)
 	if ok != true {
-		return g, errors.New("Could not convert generic definition into aws format")
+		return g, libmapper.NewError(libmapper.ErrCodeInvalidDefinition, "Could not convert generic definition into aws format")
 	}
 
 	// Map basic component values from definition
@@ -46,15 +62,17 @@ func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error)
 		c.Rebuild(g)
 
 		// Validate Components
+		m.log().Debug("validating component", logger.Fields{"component": c.GetID()})
+
 		err := c.Validate()
 		if err != nil {
-			return g, err
+			return g, libmapper.ComponentError(c, err)
 		}
 
 		// Build internal & template values
 		for _, dep := range c.Dependencies() {
 			if g.HasComponent(dep) != true {
-				return g, errors.New("Component '" + c.GetID() + "': Could not resolve component dependency '" + dep + "'")
+				return g, libmapper.DependencyError(c, dep)
 			}
 		}
 
@@ -67,6 +85,22 @@ func (m Mapper) ConvertDefinition(gd libmapper.Definition) (*graph.Graph, error)
 	return g, nil
 }
 
+// ValidateDefinition : validates every component of the input definition, collecting all errors found
+func (m Mapper) ValidateDefinition(gd libmapper.Definition) libmapper.Errors {
+	g := graph.New()
+
+	d, ok := gd.(*def.Definition)
+	if ok != true {
+		return libmapper.Errors{libmapper.NewError(libmapper.ErrCodeInvalidDefinition, "Could not convert generic definition into vcloud format")}
+	}
+
+	var errs libmapper.Errors
+
+	errs = errs.Append(mapComponents(d, g), libmapper.ErrCodeInvalidDefinition)
+
+	return append(errs, libmapper.ValidateGraph(g)...)
+}
+
 // ConvertGraph : converts the service graph into an input yaml format
 func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 	var d def.Definition
@@ -77,19 +111,22 @@ func (m Mapper) ConvertGraph(g *graph.Graph) (libmapper.Definition, error) {
 
 		for _, dep := range c.Dependencies() {
 			if g.HasComponent(dep) != true {
-				return g, errors.New("Component '" + c.GetID() + "': Could not resolve component dependency '" + dep + "'")
+				return g, libmapper.DependencyError(c, dep)
 			}
 		}
 
 		err := c.Validate()
 		if err != nil {
-			return d, err
+			return d, libmapper.ComponentError(c, err)
 		}
 	}
 
 	d.Gateways = MapDefinitionGateways(g)
 	d.Instances = MapDefinitionInstances(g)
 
+	// secret values resolved when the definition was mapped are returned as their references
+	libmapper.RestoreSecrets(&d, libmapper.GraphSecretReferences(g))
+
 	return &d, nil
 }
 
@@ -102,6 +139,16 @@ func (m Mapper) LoadDefinition(gd map[string]interface{}) (libmapper.Definition,
 	return &d, err
 }
 
+// UnknownDefinitionKeys : returns an error for every key of the input definition that doesn't match a definition field
+func (m Mapper) UnknownDefinitionKeys(gd map[string]interface{}) libmapper.Errors {
+	return libmapper.UnknownKeys(def.New(), gd)
+}
+
+// ComponentTypes : returns all component types definitions are mapped to
+func (m Mapper) ComponentTypes() []string {
+	return append([]string{}, SUPPORTEDCOMPONENTS...)
+}
+
 // LoadGraph : returns a generic interal graph
 func (m Mapper) LoadGraph(gg map[string]interface{}) (*graph.Graph, error) {
 	g := graph.New()
@@ -166,6 +213,27 @@ func (m Mapper) CreateImportGraph(params []string) *graph.Graph {
 	return g
 }
 
+// GroupComponents : groups imported instances that were not created by ernest
+func (m Mapper) GroupComponents(g *graph.Graph) libmapper.Errors {
+	return libmapper.GroupComponents(g, components.TYPEINSTANCE, libmapper.Grouping{
+		Tag: components.GROUPINSTANCE,
+		Signature: func(c graph.Component) string {
+			i := c.(*components.Instance)
+			return fmt.Sprint(i.Cpus, i.Memory, i.Catalog, i.Image, i.Network)
+		},
+		IP: func(c graph.Component) string {
+			return c.(*components.Instance).IP
+		},
+		SetGroup: func(c graph.Component, group string) {
+			i := c.(*components.Instance)
+			if i.Tags == nil {
+				i.Tags = make(map[string]string)
+			}
+			i.Tags[components.GROUPINSTANCE] = group
+		},
+	})
+}
+
 // ProviderCredentials : maps aws credentials to a generic component
 func (m Mapper) ProviderCredentials(details map[string]interface{}) graph.Component {
 	credentials := make(graph.GenericComponent)
@@ -190,27 +258,19 @@ func (m Mapper) ProviderCredentials(details map[string]interface{}) graph.Compon
 
 func mapComponents(d *def.Definition, g *graph.Graph) error {
 	// Map basic component values from definition
+	var errs libmapper.Errors
 
 	for _, gateway := range MapGateways(d) {
-		err := g.AddComponent(gateway)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(gateway), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, network := range MapNetworks(d) {
-		err := g.AddComponent(network)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(network), libmapper.ErrCodeInvalidDefinition)
 	}
 
 	for _, instance := range MapInstances(d) {
-		err := g.AddComponent(instance)
-		if err != nil {
-			return err
-		}
+		errs = errs.Append(g.AddComponent(instance), libmapper.ErrCodeInvalidDefinition)
 	}
 
-	return nil
+	return errs.Err()
 }
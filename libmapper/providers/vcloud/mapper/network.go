@@ -7,7 +7,6 @@ package mapper
 import (
 	"encoding/hex"
 	"fmt"
-	"log"
 	"net"
 	"strings"
 
@@ -22,17 +21,21 @@ func MapNetworks(d *definition.Definition) []*components.Network {
 
 	for _, r := range d.Gateways {
 		for _, network := range r.Networks {
-			octets := getIPOctets(network.Subnet)
-
 			n := &components.Network{
-				Name:         network.Name,
-				Subnet:       network.Subnet,
-				StartAddress: octets + ".5",
-				EndAddress:   octets + ".250",
-				Gateway:      octets + ".1",
-				Netmask:      parseNetmask(network.Subnet),
-				DNS:          network.DNS,
-				EdgeGateway:  r.Name,
+				Name:        network.Name,
+				Subnet:      network.Subnet,
+				DNS:         network.DNS,
+				EdgeGateway: r.Name,
+			}
+
+			// networks with an invalid range are left without addresses,
+			// and their range is reported when the network is validated
+			octets, err := getIPOctets(network.Subnet)
+			if err == nil {
+				n.StartAddress = octets + ".5"
+				n.EndAddress = octets + ".250"
+				n.Gateway = octets + ".1"
+				n.Netmask = parseNetmask(network.Subnet)
 			}
 
 			n.SetDefaultVariables()
@@ -44,16 +47,16 @@ func MapNetworks(d *definition.Definition) []*components.Network {
 	return networks
 }
 
-func getIPOctets(rng string) string {
+func getIPOctets(rng string) (string, error) {
 	// Splits the network range and returns the first three octets
 	ip, _, err := net.ParseCIDR(rng)
 	if err != nil {
-		log.Println(err)
+		return "", err
 	}
 	octets := strings.Split(ip.String(), ".")
 	octets = append(octets[:3], octets[3+1:]...)
 	octetString := strings.Join(octets, ".")
-	return octetString
+	return octetString, nil
 }
 
 func parseNetmask(rng string) string {
@@ -0,0 +1,27 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package mapper
+
+import (
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers/vcloud/components"
+	def "github.com/ernestio/definition-mapper/libmapper/providers/vcloud/definition"
+)
+
+// DefinitionSchema : returns a json schema describing the vcloud definition format
+func (m Mapper) DefinitionSchema() map[string]interface{} {
+	enums := map[string][]string{
+		"routers.firewall_rules.protocol": {
+			components.PROTOCOLTCP,
+			components.PROTOCOLUDP,
+			components.PROTOCOLICMP,
+			components.PROTOCOLANY,
+			components.PROTOCOLTCPUDP,
+		},
+		"routers.firewall_rules.action": {"allow", "drop"},
+	}
+
+	return libmapper.Schema(def.New(), enums)
+}
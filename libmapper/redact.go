@@ -0,0 +1,189 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/r3labs/graph"
+)
+
+// REDACTED : replaces the value of sensitive fields in output shown to users
+const REDACTED = "(sensitive)"
+
+// SENSITIVETAG : the struct tag marking a component field as sensitive, i.e. `sensitive:"true"`
+const SENSITIVETAG = "sensitive"
+
+// SENSITIVEKEYS : keys that are always treated as sensitive. These cover generic
+// components, such as credentials, and component fields of types not declared
+// by the mapper, which can not be tagged
+var SENSITIVEKEYS = []string{
+	"aws_secret_access_key",
+	"azure_client_secret",
+	"client_secret",
+	"password",
+	"vcloud_password",
+	"database_password",
+	"administrator_login_password",
+	"admin_password",
+}
+
+// SensitiveFields : returns the json keys of all sensitive fields of the given components
+func SensitiveFields(components ...interface{}) map[string]bool {
+	fields := make(map[string]bool)
+
+	for _, k := range SENSITIVEKEYS {
+		fields[k] = true
+	}
+
+	for _, c := range components {
+		sensitiveFields(reflect.TypeOf(c), fields)
+	}
+
+	return fields
+}
+
+// RedactGraph : encodes a graph as json, replacing the values of all sensitive
+// fields on its components, changes and changelogs
+func RedactGraph(g *graph.Graph) ([]byte, error) {
+	return redactGraph(g, true)
+}
+
+// RedactChangelogs : encodes a graph as json, replacing sensitive values on its
+// changelogs only. Component values are kept, so the graph can still be built
+func RedactChangelogs(g *graph.Graph) ([]byte, error) {
+	return redactGraph(g, false)
+}
+
+func redactGraph(g *graph.Graph, all bool) ([]byte, error) {
+	var v interface{}
+
+	data, err := g.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]interface{}, len(g.Components))
+	for i := range g.Components {
+		components[i] = g.Components[i]
+	}
+
+	err = decode(data, &v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(redact(v, SensitiveFields(components...), all))
+}
+
+// decode : decodes json, keeping numbers as they were encoded
+func decode(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	return d.Decode(v)
+}
+
+// redact : replaces the values of sensitive fields on decoded json. Changelog
+// entries are redacted when their path contains a sensitive field, while
+// other values are only redacted if all is set
+func redact(v interface{}, fields map[string]bool, all bool) interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		for i := range x {
+			x[i] = redact(x[i], fields, all)
+		}
+	case map[string]interface{}:
+		if isChange(x) && sensitivePath(x["path"].([]interface{}), fields) {
+			x["from"] = redactValue(x["from"])
+			x["to"] = redactValue(x["to"])
+			return x
+		}
+
+		for k := range x {
+			if all && fields[k] {
+				x[k] = redactValue(x[k])
+				continue
+			}
+			x[k] = redact(x[k], fields, all)
+		}
+	}
+
+	return v
+}
+
+// redactValue : unset values are kept, so it is clear that a sensitive field has not been set
+func redactValue(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+
+	return REDACTED
+}
+
+// isChange : returns true if a decoded value is a changelog entry
+func isChange(m map[string]interface{}) bool {
+	_, ok := m["path"].([]interface{})
+	if !ok {
+		return false
+	}
+
+	_, from := m["from"]
+	_, to := m["to"]
+
+	return from && to
+}
+
+func sensitivePath(path []interface{}, fields map[string]bool) bool {
+	for _, p := range path {
+		if s, ok := p.(string); ok && fields[s] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sensitiveFields : collects the keys of all tagged fields of a type, along with any nested types
+func sensitiveFields(t reflect.Type, fields map[string]bool) {
+	visited := make(map[reflect.Type]bool)
+	collectSensitive(t, fields, visited)
+}
+
+func collectSensitive(t reflect.Type, fields map[string]bool, visited map[reflect.Type]bool) {
+	if t == nil {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || visited[t] {
+		return
+	}
+
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		collectSensitive(f.Type, fields, visited)
+
+		if f.Tag.Get(SENSITIVETAG) != "true" {
+			continue
+		}
+
+		fields[jsonKey(f)] = true
+
+		// changelogs are keyed by the field's diff name
+		if name := strings.Split(f.Tag.Get("diff"), ",")[0]; name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package libmapper_test
+
+// Basic imports
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// RedactTestSuite : Test suite for redacting sensitive values
+type RedactTestSuite struct {
+	suite.Suite
+	Graph *graph.Graph
+}
+
+// SetupTest : Setup test suite
+func (suite *RedactTestSuite) SetupTest() {
+	credentials := graph.GenericComponent{
+		"_component":            "credentials",
+		"_component_id":         "credentials::aws",
+		"aws_access_key_id":     "key",
+		"aws_secret_access_key": "secret",
+	}
+
+	suite.Graph = graph.New()
+	_ = suite.Graph.AddComponent(&credentials)
+	_ = suite.Graph.AddComponent(&components.RDSInstance{
+		ComponentID:      "rds_instance::db",
+		Name:             "db",
+		DatabasePassword: "password",
+	})
+}
+
+func (suite *RedactTestSuite) components(data []byte) []map[string]interface{} {
+	var g struct {
+		Components []map[string]interface{} `json:"components"`
+	}
+
+	suite.Nil(json.Unmarshal(data, &g))
+
+	return g.Components
+}
+
+// TestSensitiveFields : Testing tagged fields are sensitive
+func (suite *RedactTestSuite) TestSensitiveFields() {
+	fields := libmapper.SensitiveFields(&components.RDSInstance{})
+	suite.True(fields["database_password"])
+	suite.True(fields["aws_secret_access_key"])
+	suite.False(fields["database_username"])
+}
+
+// TestRedactGraph : Testing all sensitive values are redacted
+func (suite *RedactTestSuite) TestRedactGraph() {
+	data, err := libmapper.RedactGraph(suite.Graph)
+	suite.Nil(err)
+
+	c := suite.components(data)
+	suite.Equal(libmapper.REDACTED, c[0]["aws_secret_access_key"])
+	suite.Equal("key", c[0]["aws_access_key_id"])
+	suite.Equal(libmapper.REDACTED, c[1]["database_password"])
+	suite.Equal("db", c[1]["name"])
+}
+
+// TestRedactChangelogs : Testing component values are kept when only redacting changelogs
+func (suite *RedactTestSuite) TestRedactChangelogs() {
+	data, err := libmapper.RedactChangelogs(suite.Graph)
+	suite.Nil(err)
+
+	c := suite.components(data)
+	suite.Equal("secret", c[0]["aws_secret_access_key"])
+	suite.Equal("password", c[1]["database_password"])
+}
+
+// TestRedactTestSuite : Test suite for redacting sensitive values
+func TestRedactTestSuite(t *testing.T) {
+	suite.Run(t, new(RedactTestSuite))
+}
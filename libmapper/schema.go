@@ -0,0 +1,159 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SCHEMAVERSION : the json schema draft generated schemas conform to
+const SCHEMAVERSION = "http://json-schema.org/draft-07/schema#"
+
+// Schema : generates a json schema from a definition type. Enums are keyed
+// by the dotted json path of the field they apply to, without indexes,
+// i.e. 'route53_zones.records.type'
+func Schema(d Definition, enums map[string][]string) map[string]interface{} {
+	s := typeSchema(reflect.TypeOf(d), "", enums)
+	s["$schema"] = SCHEMAVERSION
+	s["required"] = []string{"name", "project"}
+	s["properties"].(map[string]interface{})[VARIABLESKEY] = variablesSchema()
+	s["properties"].(map[string]interface{})[MODULESKEY] = modulesSchema()
+
+	return s
+}
+
+// CaseInsensitive : returns enum values in both lower case and as given, for
+// fields whose case is normalised when mapped or by the provider
+func CaseInsensitive(values []string) []string {
+	var all []string
+
+	for _, v := range values {
+		if l := strings.ToLower(v); l != v {
+			all = append(all, l)
+		}
+		all = append(all, v)
+	}
+
+	return all
+}
+
+// variablesSchema : variables are declared with a type, default, required flag and description, or only a default value
+func variablesSchema() map[string]interface{} {
+	declaration := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type":        map[string]interface{}{"type": "string", "enum": VARIABLETYPES},
+			"default":     map[string]interface{}{},
+			"required":    map[string]interface{}{"type": "boolean"},
+			"description": map[string]interface{}{"type": "string"},
+		},
+		"additionalProperties": false,
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"additionalProperties": map[string]interface{}{
+			"anyOf": []interface{}{
+				declaration,
+				map[string]interface{}{"type": []string{"string", "integer", "number", "boolean", "array"}},
+			},
+		},
+	}
+}
+
+// modulesSchema : modules are used with a name, a source and the values of the module's variables
+func modulesSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":       map[string]interface{}{"type": "string"},
+				"source":     map[string]interface{}{"type": "string"},
+				"parameters": map[string]interface{}{"type": "object"},
+			},
+			"required":             []string{"name", "source"},
+			"additionalProperties": false,
+		},
+	}
+}
+
+// scalarSchema : non string values can also be set by a variable reference
+func scalarSchema(t string) map[string]interface{} {
+	return variableOf(map[string]interface{}{"type": t})
+}
+
+// variableOf : allows a value to be set either as described by the schema, or by a variable reference
+func variableOf(s map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"anyOf": []interface{}{
+			s,
+			map[string]interface{}{"type": "string", "pattern": "^" + variableReference.String() + "$"},
+		},
+	}
+}
+
+func typeSchema(t reflect.Type, path string, enums map[string][]string) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s := make(map[string]interface{})
+
+	switch t.Kind() {
+	case reflect.String:
+		s["type"] = "string"
+		if values, ok := enums[path]; ok {
+			s["enum"] = values
+			s = variableOf(s)
+		}
+	case reflect.Bool:
+		s = scalarSchema("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = scalarSchema("integer")
+	case reflect.Float32, reflect.Float64:
+		s = scalarSchema("number")
+	case reflect.Slice, reflect.Array:
+		s["type"] = "array"
+		s["items"] = typeSchema(t.Elem(), path, enums)
+	case reflect.Map:
+		s["type"] = "object"
+		if t.Elem().Kind() != reflect.Interface {
+			s["additionalProperties"] = typeSchema(t.Elem(), path, enums)
+		}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		structSchema(t, path, enums, properties)
+
+		s["type"] = "object"
+		s["properties"] = properties
+		s["additionalProperties"] = false
+	}
+
+	return s
+}
+
+func structSchema(t reflect.Type, path string, enums map[string][]string, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			structSchema(f.Type, path, enums, properties)
+			continue
+		}
+
+		key := jsonKey(f)
+		if key == "-" {
+			continue
+		}
+
+		properties[key] = typeSchema(f.Type, joinPath(path, key), enums)
+	}
+}
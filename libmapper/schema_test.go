@@ -0,0 +1,61 @@
+package libmapper_test
+
+// Basic imports
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	def "github.com/ernestio/definition-mapper/libmapper/providers/aws/definition"
+	"github.com/stretchr/testify/suite"
+)
+
+// SchemaTestSuite : Test suite for definition schemas
+type SchemaTestSuite struct {
+	suite.Suite
+	Schema map[string]interface{}
+}
+
+// SetupTest : Setup test suite
+func (suite *SchemaTestSuite) SetupTest() {
+	suite.Schema = libmapper.Schema(def.New(), map[string][]string{
+		"route53_zones.records.type": {"A", "CNAME"},
+	})
+}
+
+func property(s map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, k := range keys {
+		if s["type"] == "array" {
+			s = s["items"].(map[string]interface{})
+		}
+		s = s["properties"].(map[string]interface{})[k].(map[string]interface{})
+	}
+	return s
+}
+
+func valueSchema(s map[string]interface{}) map[string]interface{} {
+	return s["anyOf"].([]interface{})[0].(map[string]interface{})
+}
+
+// TestSchema : Testing definition types are mapped to a json schema
+func (suite *SchemaTestSuite) TestSchema() {
+	suite.Equal(libmapper.SCHEMAVERSION, suite.Schema["$schema"])
+	suite.Equal("object", suite.Schema["type"])
+	suite.Equal(false, suite.Schema["additionalProperties"])
+
+	suite.Equal("array", property(suite.Schema, "instances")["type"])
+	suite.Equal("integer", valueSchema(property(suite.Schema, "instances", "count"))["type"])
+	suite.Equal("boolean", valueSchema(property(suite.Schema, "instances", "elastic_ip"))["type"])
+	suite.Equal("integer", valueSchema(property(suite.Schema, "rds_instances", "storage", "size"))["type"])
+	suite.Equal("object", property(suite.Schema, "iam_policies", "policy_document")["type"])
+
+	records := valueSchema(property(suite.Schema, "route53_zones", "records", "type"))
+	suite.Equal([]string{"A", "CNAME"}, records["enum"])
+	suite.Nil(property(suite.Schema, "route53_zones", "name")["enum"])
+
+	suite.Equal("object", property(suite.Schema, "variables")["type"])
+}
+
+// TestSchemaTestSuite : tests for definition schemas
+func TestSchemaTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
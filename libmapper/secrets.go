@@ -0,0 +1,367 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/r3labs/graph"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// SECRETPREFIX : the default prefix of environment variables holding secrets
+const SECRETPREFIX = "SECRET_"
+
+// SECRETSFILE : the environment variable setting the secrets file used to resolve secrets
+const SECRETSFILE = "SECRETS_FILE"
+
+// SECRETREFERENCES : the key of a mapping's credentials holding the secret
+// references of the definition it was mapped from
+const SECRETREFERENCES = "_secret_references"
+
+// secretURI : a secret referenced as a whole value, i.e. 'secret://database/password'
+var secretURI = regexp.MustCompile(`^secret://([a-zA-Z0-9_\-\.]+(?:/[a-zA-Z0-9_\-\.]+)*)$`)
+
+// secretReference : a secret referenced within a value, i.e. '${secret.database/password}'
+var secretReference = regexp.MustCompile(`\$\{secret\.([a-zA-Z0-9_\-\./]+)\}`)
+
+// SecretResolver : resolves the value of a secret referenced by a definition.
+// Secret names are paths, separated by '/'
+type SecretResolver interface {
+	Resolve(name string) (string, error)
+}
+
+// EnvSecretResolver : resolves secrets from environment variables, named by the
+// prefix followed by the upper cased secret name, with any separators replaced
+// by '_'. i.e. 'database/password' is resolved from 'SECRET_DATABASE_PASSWORD'
+type EnvSecretResolver struct {
+	Prefix string
+}
+
+// Resolve : returns the value of a secret
+func (r EnvSecretResolver) Resolve(name string) (string, error) {
+	key := r.Prefix + strings.ToUpper(strings.NewReplacer("/", "_", "-", "_", ".", "_").Replace(name))
+
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", errors.New("environment variable '" + key + "' is not set")
+	}
+
+	return value, nil
+}
+
+// FileSecretResolver : resolves secrets from a yaml or json file. Each part of
+// a secret's name is a key of the file's nested maps
+type FileSecretResolver struct {
+	Secrets map[string]interface{}
+}
+
+// NewFileSecretResolver : loads a secrets file
+func NewFileSecretResolver(path string) (*FileSecretResolver, error) {
+	var secrets map[string]interface{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(data, &secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSecretResolver{Secrets: secrets}, nil
+}
+
+// Resolve : returns the value of a secret
+func (r *FileSecretResolver) Resolve(name string) (string, error) {
+	var v interface{} = r.Secrets
+
+	for _, key := range strings.Split(name, "/") {
+		m, ok := stringMap(v)
+		if !ok {
+			return "", errors.New("secrets file does not contain '" + name + "'")
+		}
+
+		v, ok = m[key]
+		if !ok {
+			return "", errors.New("secrets file does not contain '" + name + "'")
+		}
+	}
+
+	switch v.(type) {
+	case nil, map[string]interface{}, map[interface{}]interface{}, []interface{}:
+		return "", errors.New("secret '" + name + "' is not a value")
+	}
+
+	return fmt.Sprint(v), nil
+}
+
+// DefaultSecretResolver : returns a file resolver if a secrets file has been
+// configured, falling back to resolving secrets from environment variables
+func DefaultSecretResolver() (SecretResolver, error) {
+	path := os.Getenv(SECRETSFILE)
+	if path == "" {
+		return EnvSecretResolver{Prefix: SECRETPREFIX}, nil
+	}
+
+	return NewFileSecretResolver(path)
+}
+
+// ResolveSecrets : returns a copy of a generic definition with all secret
+// references replaced by the value of the secret. Secrets can be referenced
+// as a whole value with 'secret://name' or within a value with '${secret.name}'
+func ResolveSecrets(d map[string]interface{}, r SecretResolver) (map[string]interface{}, Errors) {
+	errs := Errors{}
+
+	resolved := make(map[string]interface{}, len(d))
+
+	for _, k := range sortedKeys(d) {
+		var rerrs Errors
+		resolved[k], rerrs = resolveSecretValue(d[k], r, k)
+		errs = append(errs, rerrs...)
+	}
+
+	return resolved, errs
+}
+
+func resolveSecretValue(v interface{}, r SecretResolver, path string) (interface{}, Errors) {
+	errs := Errors{}
+
+	switch x := v.(type) {
+	case string:
+		return resolveSecretString(x, r, path)
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i := range x {
+			var rerrs Errors
+			l[i], rerrs = resolveSecretValue(x[i], r, path+"["+strconv.Itoa(i)+"]")
+			errs = append(errs, rerrs...)
+		}
+		return l, errs
+	case map[string]interface{}, map[interface{}]interface{}:
+		m, _ := stringMap(x)
+		rm := make(map[string]interface{}, len(m))
+		for _, k := range sortedKeys(m) {
+			var rerrs Errors
+			rm[k], rerrs = resolveSecretValue(m[k], r, joinPath(path, k))
+			errs = append(errs, rerrs...)
+		}
+		return rm, errs
+	}
+
+	return v, errs
+}
+
+func resolveSecretString(s string, r SecretResolver, path string) (interface{}, Errors) {
+	errs := Errors{}
+
+	if m := secretURI.FindStringSubmatch(s); m != nil {
+		value, err := r.Resolve(m[1])
+		if err != nil {
+			return s, Errors{unresolvedSecret(m[1], path, err)}
+		}
+		return value, errs
+	}
+
+	rs := secretReference.ReplaceAllStringFunc(s, func(ref string) string {
+		name := secretReference.FindStringSubmatch(ref)[1]
+
+		value, err := r.Resolve(name)
+		if err != nil {
+			errs = append(errs, unresolvedSecret(name, path, err))
+			return ref
+		}
+
+		return value
+	})
+
+	return rs, errs
+}
+
+func unresolvedSecret(name, path string, err error) Error {
+	return NewFieldError(ErrCodeUnresolvedSecret, path, "secret '"+name+"' could not be resolved: "+err.Error())
+}
+
+// SecretReferences : returns the values of a generic definition that
+// reference secrets, by their path. Entries of lists are identified by their
+// name, i.e. 'rds_instances[db].database_password', so references can be
+// put back on definitions converted from a mapping, whatever their order
+func SecretReferences(d map[string]interface{}) map[string]string {
+	refs := make(map[string]string)
+
+	for _, k := range sortedKeys(d) {
+		collectSecretReferences(d[k], k, refs)
+	}
+
+	return refs
+}
+
+func collectSecretReferences(v interface{}, path string, refs map[string]string) {
+	switch x := v.(type) {
+	case string:
+		if secretURI.MatchString(x) || secretReference.MatchString(x) {
+			refs[path] = x
+		}
+	case []interface{}:
+		for i := range x {
+			m, _ := stringMap(x[i])
+			name, _ := m["name"].(string)
+			collectSecretReferences(x[i], path+"["+entryKey(name, i)+"]", refs)
+		}
+	case map[string]interface{}, map[interface{}]interface{}:
+		m, _ := stringMap(x)
+		for _, k := range sortedKeys(m) {
+			collectSecretReferences(m[k], joinPath(path, k), refs)
+		}
+	}
+}
+
+// SetSecretReferences : records the secret references of a definition on
+// the credentials of its mapping
+func SetSecretReferences(credentials graph.Component, refs map[string]string) {
+	gc, ok := credentials.(*graph.GenericComponent)
+	if !ok || len(refs) < 1 {
+		return
+	}
+
+	(*gc)[SECRETREFERENCES] = refs
+}
+
+// GraphSecretReferences : returns the secret references recorded on the credentials of a mapping
+func GraphSecretReferences(g *graph.Graph) map[string]string {
+	refs := make(map[string]string)
+
+	for _, c := range g.GetComponents().ByType("credentials") {
+		gc, ok := c.(*graph.GenericComponent)
+		if !ok {
+			continue
+		}
+
+		switch x := (*gc)[SECRETREFERENCES].(type) {
+		case map[string]string:
+			for k, v := range x {
+				refs[k] = v
+			}
+		case map[string]interface{}:
+			for k, v := range x {
+				refs[k], _ = v.(string)
+			}
+		}
+	}
+
+	return refs
+}
+
+// RestoreSecrets : sets the values of a definition that were resolved from
+// secrets back to their references, so secret values resolved when the
+// definition was mapped are never returned. Other values are left as they are
+func RestoreSecrets(d interface{}, refs map[string]string) {
+	if len(refs) < 1 {
+		return
+	}
+
+	restoreSecrets(reflect.ValueOf(d), "", refs)
+}
+
+func restoreSecrets(v reflect.Value, path string, refs map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			restoreSecrets(v.Elem(), path, refs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			restoreSecrets(v.Index(i), path+"["+entryKey(structName(v.Index(i)), i)+"]", refs)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return
+		}
+
+		for _, k := range v.MapKeys() {
+			if ref, ok := refs[joinPath(path, k.String())]; ok {
+				v.SetMapIndex(k, reflect.ValueOf(ref).Convert(v.Type().Elem()))
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			key, inline := yamlKey(v.Type().Field(i))
+
+			switch {
+			case inline:
+				restoreSecrets(v.Field(i), path, refs)
+			case key != "":
+				restoreSecrets(v.Field(i), joinPath(path, key), refs)
+			}
+		}
+	case reflect.String:
+		if ref, ok := refs[path]; ok && v.CanSet() {
+			v.SetString(ref)
+		}
+	}
+}
+
+// yamlKey : returns the key a struct field is set by on a definition
+func yamlKey(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+
+	parts := strings.Split(f.Tag.Get("yaml"), ",")
+
+	for _, p := range parts[1:] {
+		if p == "inline" {
+			return "", true
+		}
+	}
+
+	switch parts[0] {
+	case "-":
+		return "", false
+	case "":
+		return strings.ToLower(f.Name), false
+	}
+
+	return parts[0], false
+}
+
+// structName : returns the name of a definition entry
+func structName(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		if key, _ := yamlKey(v.Type().Field(i)); key == "name" && v.Field(i).Kind() == reflect.String {
+			return v.Field(i).String()
+		}
+	}
+
+	return ""
+}
+
+// entryKey : identifies an entry of a list by its name, or by its index if it has none
+func entryKey(name string, i int) string {
+	if name != "" {
+		return name
+	}
+
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,96 @@
+package libmapper_test
+
+// Basic imports
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	def "github.com/ernestio/definition-mapper/libmapper/providers/aws/definition"
+	"github.com/stretchr/testify/suite"
+)
+
+// SecretsTestSuite : Test suite for secret references
+type SecretsTestSuite struct {
+	suite.Suite
+	Definition map[string]interface{}
+}
+
+// SetupTest : Setup test suite
+func (suite *SecretsTestSuite) SetupTest() {
+	suite.Definition = map[string]interface{}{
+		"name": "test",
+		"rds_instances": []interface{}{
+			map[string]interface{}{
+				"name":              "db",
+				"database_password": "secret://db/password",
+				"database_username": "${secret.db/username}-admin",
+			},
+		},
+	}
+}
+
+// TestEnvSecretResolver : Testing secrets are resolved from environment variables
+func (suite *SecretsTestSuite) TestEnvSecretResolver() {
+	os.Setenv("TEST_SECRET_DB_PASSWORD", "password")
+	os.Setenv("TEST_SECRET_DB_USERNAME", "user")
+	defer os.Unsetenv("TEST_SECRET_DB_PASSWORD")
+	defer os.Unsetenv("TEST_SECRET_DB_USERNAME")
+
+	d, errs := libmapper.ResolveSecrets(suite.Definition, libmapper.EnvSecretResolver{Prefix: "TEST_SECRET_"})
+	suite.Len(errs, 0)
+
+	db := d["rds_instances"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("password", db["database_password"])
+	suite.Equal("user-admin", db["database_username"])
+}
+
+// TestFileSecretResolver : Testing secrets are resolved from a secrets file
+func (suite *SecretsTestSuite) TestFileSecretResolver() {
+	f, err := ioutil.TempFile("", "secrets")
+	suite.Nil(err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString("db:\n  password: password\n")
+	suite.Nil(err)
+	suite.Nil(f.Close())
+
+	r, err := libmapper.NewFileSecretResolver(f.Name())
+	suite.Nil(err)
+
+	d, errs := libmapper.ResolveSecrets(suite.Definition, r)
+	suite.Len(errs, 1)
+	suite.Equal(libmapper.ErrCodeUnresolvedSecret, errs[0].Code)
+	suite.Equal("rds_instances[0].database_username", errs[0].Field)
+
+	db := d["rds_instances"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("password", db["database_password"])
+}
+
+// TestRestoreSecrets : Testing secret references are put back on definitions
+func (suite *SecretsTestSuite) TestRestoreSecrets() {
+	refs := libmapper.SecretReferences(suite.Definition)
+	suite.Equal(map[string]string{
+		"rds_instances[db].database_password": "secret://db/password",
+		"rds_instances[db].database_username": "${secret.db/username}-admin",
+	}, refs)
+
+	d := def.Definition{
+		RDSInstances: []def.RDSInstance{
+			{Name: "other", DatabasePassword: "plain"},
+			{Name: "db", DatabasePassword: "password", DatabaseUsername: "user-admin", DatabaseName: "app"},
+		},
+	}
+
+	libmapper.RestoreSecrets(&d, refs)
+	suite.Equal("plain", d.RDSInstances[0].DatabasePassword)
+	suite.Equal("secret://db/password", d.RDSInstances[1].DatabasePassword)
+	suite.Equal("${secret.db/username}-admin", d.RDSInstances[1].DatabaseUsername)
+	suite.Equal("app", d.RDSInstances[1].DatabaseName)
+}
+
+// TestSecretsTestSuite : Test suite for secret references
+func TestSecretsTestSuite(t *testing.T) {
+	suite.Run(t, new(SecretsTestSuite))
+}
@@ -0,0 +1,145 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/r3labs/graph"
+)
+
+// ValidateGraph : validates every component of a graph, collecting all
+// validation and dependency errors rather than stopping at the first
+func ValidateGraph(g *graph.Graph) Errors {
+	errs := Errors{}
+
+	for _, c := range g.Components {
+		c.Rebuild(g)
+
+		err := c.Validate()
+		if err != nil {
+			errs = append(errs, ComponentError(c, err))
+		}
+
+		for _, dep := range c.Dependencies() {
+			if g.HasComponent(dep) != true {
+				errs = append(errs, DependencyError(c, dep))
+			}
+		}
+	}
+
+	return errs
+}
+
+// DecodeErrors : converts the errors returned when decoding a definition into validation errors
+func DecodeErrors(err error) Errors {
+	derr, ok := err.(*mapstructure.Error)
+	if !ok {
+		return ToErrors(err, ErrCodeInvalidDefinition)
+	}
+
+	errs := make(Errors, len(derr.Errors))
+
+	for i, msg := range derr.Errors {
+		errs[i] = NewError(ErrCodeInvalidType, msg)
+
+		// decoder errors are prefixed by the quoted field path
+		if strings.HasPrefix(msg, "'") {
+			if end := strings.Index(msg[1:], "'"); end > -1 {
+				errs[i].Field = msg[1 : end+1]
+			}
+		}
+	}
+
+	return errs
+}
+
+// FieldPath : returns the json path of the field of a component that
+// references the given component id, either by name or by template.
+// Fields named after the referenced component type are preferred
+func FieldPath(c graph.Component, id string) string {
+	parts := strings.SplitN(id, "::", 2)
+	name := parts[len(parts)-1]
+
+	match := func(s string) bool {
+		return s == name || strings.Contains(s, id)
+	}
+
+	if len(parts) > 1 {
+		ctype := parts[0]
+
+		p := findField(reflect.ValueOf(c), "", func(path, s string) bool {
+			return strings.Contains(path, ctype) && match(s)
+		})
+
+		if p != "" {
+			return p
+		}
+	}
+
+	return findField(reflect.ValueOf(c), "", func(path, s string) bool {
+		return match(s)
+	})
+}
+
+func findField(v reflect.Value, path string, match func(string, string) bool) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if match(path, v.String()) {
+			return path
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if p := findField(v.Index(i), path+"["+strconv.Itoa(i)+"]", match); p != "" {
+				return p
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" || strings.HasPrefix(name, "_") {
+				continue
+			}
+
+			fp := path
+			if !f.Anonymous {
+				if name == "" {
+					name = f.Name
+				}
+				fp = joinPath(path, name)
+			}
+
+			if p := findField(v.Field(i), fp, match); p != "" {
+				return p
+			}
+		}
+	}
+
+	return ""
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
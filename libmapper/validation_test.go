@@ -0,0 +1,79 @@
+package libmapper_test
+
+// Basic imports
+import (
+	"errors"
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/mitchellh/mapstructure"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// ValidationTestSuite : Test suite for definition validation
+type ValidationTestSuite struct {
+	suite.Suite
+	Graph *graph.Graph
+}
+
+// SetupTest : Setup test suite
+func (suite *ValidationTestSuite) SetupTest() {
+	suite.Graph = graph.New()
+
+	ebs := &components.EBSVolume{Name: "data"}
+	ebs.SetDefaultVariables()
+
+	instance := &components.Instance{
+		Name:           "web-1",
+		Type:           "t2.micro",
+		Image:          "ami-123456",
+		Network:        "public",
+		SecurityGroups: []string{"web-sg"},
+	}
+	instance.SetDefaultVariables()
+
+	_ = suite.Graph.AddComponent(ebs)
+	_ = suite.Graph.AddComponent(instance)
+}
+
+// TestValidateGraph : Testing all errors are collected
+func (suite *ValidationTestSuite) TestValidateGraph() {
+	errs := libmapper.ValidateGraph(suite.Graph)
+	suite.Equal(3, len(errs))
+
+	suite.Equal("ebs_volume::data", errs[0].ComponentID)
+	suite.Equal(components.EBSErrAvailabilityNameNil, errs[0].Message)
+	suite.Equal(libmapper.ErrCodeRequired, errs[0].Code)
+	suite.Equal("availability_zone", errs[0].Field)
+	suite.Equal(libmapper.SeverityError, errs[0].Severity)
+
+	suite.Equal("instance::web-1", errs[1].ComponentID)
+	suite.Equal("instance", errs[1].ComponentType)
+	suite.Equal("security_groups[0]", errs[1].Field)
+	suite.Equal("Could not resolve component dependency 'firewall::web-sg'", errs[1].Message)
+	suite.Equal(libmapper.ErrCodeUnresolvedDependency, errs[1].Code)
+
+	suite.Equal("network_name", errs[2].Field)
+}
+
+// TestDecodeErrors : Testing decoder errors are mapped to their field
+func (suite *ValidationTestSuite) TestDecodeErrors() {
+	err := &mapstructure.Error{Errors: []string{"'instances[0].count' expected type 'int', got unconvertible type 'string'"}}
+
+	errs := libmapper.DecodeErrors(err)
+	suite.Equal(1, len(errs))
+	suite.Equal("instances[0].count", errs[0].Field)
+	suite.Equal(libmapper.ErrCodeInvalidType, errs[0].Code)
+
+	errs = libmapper.DecodeErrors(errors.New("failure"))
+	suite.Equal("failure", errs[0].Message)
+	suite.Equal("", errs[0].Field)
+	suite.Equal(libmapper.ErrCodeInvalidDefinition, errs[0].Code)
+}
+
+// TestValidationTestSuite : tests for definition validation
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
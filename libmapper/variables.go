@@ -0,0 +1,322 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package libmapper
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// VARIABLESKEY : the definition key variables are declared on
+const VARIABLESKEY = "variables"
+
+// VARIABLETYPES : supported variable types
+var VARIABLETYPES = []string{"string", "integer", "number", "bool", "list", "map"}
+
+// VARIABLEKEYS : the keys of a variable declaration. Maps holding any other
+// key are taken as the default value of a variable
+var VARIABLEKEYS = []string{"default", "type", "required", "description"}
+
+var variableReference = regexp.MustCompile(`\$\{var\.([a-zA-Z0-9_-]+)\}`)
+
+// invalidVariable : marks a variable that has already been reported as
+// invalid, so references to it are not reported again
+type invalidVariable struct{}
+
+// Variable : a variable declared on a definition
+type Variable struct {
+	Type        string
+	Default     interface{}
+	Required    bool
+	Description string
+}
+
+// ResolveVariables : returns a copy of a generic definition with all
+// '${var.name}' references replaced by the value of the variable. Values
+// are taken from the overrides, falling back to the variable's default.
+// References that make up a whole value keep the type of the variable
+func ResolveVariables(d map[string]interface{}, overrides map[string]interface{}) (map[string]interface{}, Errors) {
+	vars, errs := declaredVariables(d[VARIABLESKEY])
+
+	for _, name := range sortedKeys(overrides) {
+		if _, ok := vars[name]; !ok {
+			errs = append(errs, NewFieldError(ErrCodeUnresolvedVariable, VARIABLESKEY+"."+name, "variable '"+name+"' is set, but is not declared on the definition"))
+		}
+	}
+
+	values := make(map[string]interface{})
+
+	for _, name := range sortedVariables(vars) {
+		v := vars[name]
+		field := VARIABLESKEY + "." + name
+
+		value, ok := overrides[name]
+		if !ok {
+			value = v.Default
+		}
+
+		if value == nil {
+			if v.Required {
+				errs = append(errs, NewFieldError(ErrCodeRequired, field, "variable '"+name+"' is required"))
+				values[name] = invalidVariable{}
+			}
+			continue
+		}
+
+		cv, err := convertVariable(v.Type, value)
+		if err != nil {
+			errs = append(errs, NewFieldError(ErrCodeInvalidType, field, "variable '"+name+"' "+err.Error()))
+			values[name] = invalidVariable{}
+			continue
+		}
+
+		values[name] = cv
+	}
+
+	resolved := make(map[string]interface{}, len(d))
+
+	for _, k := range sortedKeys(d) {
+		if k == VARIABLESKEY {
+			continue
+		}
+
+		var rerrs Errors
+		resolved[k], rerrs = resolveValue(d[k], values, k)
+		errs = append(errs, rerrs...)
+	}
+
+	return resolved, errs
+}
+
+func declaredVariables(i interface{}) (map[string]Variable, Errors) {
+	errs := Errors{}
+	vars := make(map[string]Variable)
+
+	if i == nil {
+		return vars, errs
+	}
+
+	decl, ok := stringMap(i)
+	if !ok {
+		return vars, Errors{NewFieldError(ErrCodeInvalidType, VARIABLESKEY, "variables must be a map of variable names to their declaration")}
+	}
+
+	for _, name := range sortedKeys(decl) {
+		field := VARIABLESKEY + "." + name
+
+		// variables can be declared with only a default value
+		m, ok := stringMap(decl[name])
+		if !ok || !isDeclaration(m) {
+			vars[name] = Variable{Default: decl[name]}
+			continue
+		}
+
+		var v Variable
+
+		v.Default = m["default"]
+		v.Type, _ = m["type"].(string)
+		v.Required, _ = m["required"].(bool)
+		v.Description, _ = m["description"].(string)
+
+		if v.Type != "" && isOneOf(VARIABLETYPES, v.Type) != true {
+			errs = append(errs, NewFieldError(ErrCodeInvalidValue, field+".type", "variable type '"+v.Type+"' is not supported, must be one of "+fmt.Sprint(VARIABLETYPES)))
+			v.Type = ""
+		}
+
+		vars[name] = v
+	}
+
+	return vars, errs
+}
+
+// isDeclaration : returns true if a map declares a variable, rather than
+// being the default value of a map variable
+func isDeclaration(m map[string]interface{}) bool {
+	if len(m) == 0 {
+		return false
+	}
+
+	for k := range m {
+		if isOneOf(VARIABLEKEYS, k) != true {
+			return false
+		}
+	}
+
+	return true
+}
+
+// convertVariable : converts a variable value to its declared type
+func convertVariable(t string, value interface{}) (interface{}, error) {
+	switch t {
+	case "string":
+		switch value.(type) {
+		case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+			return nil, fmt.Errorf("expected a string, got '%v'", value)
+		}
+		return fmt.Sprint(value), nil
+	case "integer":
+		switch x := value.(type) {
+		case int:
+			return x, nil
+		case int64:
+			return int(x), nil
+		case float64:
+			if x == math.Trunc(x) {
+				return int(x), nil
+			}
+		case string:
+			if i, err := strconv.Atoi(x); err == nil {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("expected an integer, got '%v'", value)
+	case "number":
+		switch x := value.(type) {
+		case int, int64, float64:
+			return x, nil
+		case string:
+			if f, err := strconv.ParseFloat(x, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("expected a number, got '%v'", value)
+	case "bool":
+		switch x := value.(type) {
+		case bool:
+			return x, nil
+		case string:
+			if b, err := strconv.ParseBool(x); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("expected a bool, got '%v'", value)
+	case "list":
+		if _, ok := value.([]interface{}); ok {
+			return value, nil
+		}
+		return nil, fmt.Errorf("expected a list, got '%v'", value)
+	case "map":
+		if m, ok := stringMap(value); ok {
+			return m, nil
+		}
+		return nil, fmt.Errorf("expected a map, got '%v'", value)
+	}
+
+	return value, nil
+}
+
+func resolveValue(v interface{}, values map[string]interface{}, path string) (interface{}, Errors) {
+	errs := Errors{}
+
+	switch x := v.(type) {
+	case string:
+		return resolveString(x, values, path)
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i := range x {
+			var rerrs Errors
+			l[i], rerrs = resolveValue(x[i], values, path+"["+strconv.Itoa(i)+"]")
+			errs = append(errs, rerrs...)
+		}
+		return l, errs
+	case map[string]interface{}, map[interface{}]interface{}:
+		m, _ := stringMap(x)
+		rm := make(map[string]interface{}, len(m))
+		for _, k := range sortedKeys(m) {
+			var rerrs Errors
+			rm[k], rerrs = resolveValue(m[k], values, joinPath(path, k))
+			errs = append(errs, rerrs...)
+		}
+		return rm, errs
+	}
+
+	return v, errs
+}
+
+func resolveString(s string, values map[string]interface{}, path string) (interface{}, Errors) {
+	errs := Errors{}
+
+	// a reference making up the whole value keeps the variable's type
+	if m := variableReference.FindStringSubmatch(s); m != nil && m[0] == s {
+		value, ok := values[m[1]]
+		if !ok {
+			return s, Errors{unresolvedVariable(m[1], path)}
+		}
+		if _, invalid := value.(invalidVariable); invalid {
+			return s, errs
+		}
+		return value, errs
+	}
+
+	r := variableReference.ReplaceAllStringFunc(s, func(ref string) string {
+		name := variableReference.FindStringSubmatch(ref)[1]
+
+		value, ok := values[name]
+		if !ok {
+			errs = append(errs, unresolvedVariable(name, path))
+			return ref
+		}
+		if _, invalid := value.(invalidVariable); invalid {
+			return ref
+		}
+
+		return fmt.Sprint(value)
+	})
+
+	return r, errs
+}
+
+func unresolvedVariable(name, path string) Error {
+	return NewFieldError(ErrCodeUnresolvedVariable, path, "variable '"+name+"' is not declared or has no value")
+}
+
+func stringMap(i interface{}) (map[string]interface{}, bool) {
+	switch x := i.(type) {
+	case map[string]interface{}:
+		return x, true
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			m[fmt.Sprint(k)] = v
+		}
+		return m, true
+	}
+
+	return nil, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedVariables(vars map[string]Variable) []string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func isOneOf(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
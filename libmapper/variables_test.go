@@ -0,0 +1,88 @@
+package libmapper_test
+
+// Basic imports
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/stretchr/testify/suite"
+)
+
+// VariablesTestSuite : Test suite for definition variables
+type VariablesTestSuite struct {
+	suite.Suite
+	Definition map[string]interface{}
+}
+
+// SetupTest : Setup test suite
+func (suite *VariablesTestSuite) SetupTest() {
+	suite.Definition = map[string]interface{}{
+		"name":    "test",
+		"project": "test",
+		"variables": map[string]interface{}{
+			"size":  "t2.micro",
+			"count": map[string]interface{}{"type": "integer", "default": 1, "description": "number of instances"},
+			"cidr":  map[string]interface{}{"type": "string", "required": true},
+			"tags":  map[string]interface{}{"team": "payments", "type": "web"},
+		},
+		"instances": []interface{}{
+			map[string]interface{}{
+				"name":  "web-${var.size}",
+				"type":  "${var.size}",
+				"count": "${var.count}",
+				"tags":  "${var.tags}",
+				"networks": map[string]interface{}{
+					"subnet": "${var.cidr}",
+				},
+			},
+		},
+	}
+}
+
+// TestResolveVariables : Testing variables are resolved from defaults and overrides
+func (suite *VariablesTestSuite) TestResolveVariables() {
+	d, errs := libmapper.ResolveVariables(suite.Definition, map[string]interface{}{"count": float64(3), "cidr": "10.0.0.0/24"})
+	suite.Equal(0, len(errs))
+	suite.Nil(d["variables"])
+
+	instance := d["instances"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("web-t2.micro", instance["name"])
+	suite.Equal("t2.micro", instance["type"])
+	suite.Equal(3, instance["count"])
+
+	// declarations can be described
+	d, errs = libmapper.ResolveVariables(suite.Definition, map[string]interface{}{"cidr": "10.0.0.0/24"})
+	suite.Equal(0, len(errs))
+	suite.Equal(1, d["instances"].([]interface{})[0].(map[string]interface{})["count"])
+	suite.Equal("10.0.0.0/24", instance["networks"].(map[string]interface{})["subnet"])
+
+	// maps with keys other than those of a declaration are default values
+	suite.Equal(map[string]interface{}{"team": "payments", "type": "web"}, instance["tags"])
+}
+
+// TestVariableErrors : Testing missing and mistyped variables are reported
+func (suite *VariablesTestSuite) TestVariableErrors() {
+	delete(suite.Definition["variables"].(map[string]interface{}), "size")
+
+	_, errs := libmapper.ResolveVariables(suite.Definition, map[string]interface{}{"count": "three", "sise": "t2.large"})
+	suite.Equal(5, len(errs))
+
+	suite.Equal(libmapper.ErrCodeUnresolvedVariable, errs[0].Code)
+	suite.Equal("variables.sise", errs[0].Field)
+
+	suite.Equal(libmapper.ErrCodeRequired, errs[1].Code)
+	suite.Equal("variables.cidr", errs[1].Field)
+
+	suite.Equal(libmapper.ErrCodeInvalidType, errs[2].Code)
+	suite.Equal("variables.count", errs[2].Field)
+
+	// references to invalid variables are not reported again
+	suite.Equal(libmapper.ErrCodeUnresolvedVariable, errs[3].Code)
+	suite.Equal("instances[0].name", errs[3].Field)
+	suite.Equal("instances[0].type", errs[4].Field)
+}
+
+// TestVariablesTestSuite : tests for definition variables
+func TestVariablesTestSuite(t *testing.T) {
+	suite.Run(t, new(VariablesTestSuite))
+}
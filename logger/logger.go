@@ -0,0 +1,328 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Level : the severity of a log line
+type Level int32
+
+const (
+	// DEBUG : detail useful when tracing a single request
+	DEBUG Level = iota
+	// INFO : requests handled and changes to the state of the service
+	INFO
+	// WARN : problems the service has recovered from
+	WARN
+	// ERROR : requests or operations that failed
+	ERROR
+)
+
+var levels = map[Level]string{
+	DEBUG: "debug",
+	INFO:  "info",
+	WARN:  "warn",
+	ERROR: "error",
+}
+
+// String : returns the name of the level
+func (l Level) String() string {
+	return levels[l]
+}
+
+// ParseLevel : returns the level with the given name
+func ParseLevel(name string) (Level, error) {
+	for l, n := range levels {
+		if strings.ToLower(name) == n {
+			return l, nil
+		}
+	}
+
+	return INFO, errors.New("unknown log level: " + name)
+}
+
+// REDACTED : replaces sensitive values on log lines
+const REDACTED = "(sensitive)"
+
+// Fields : values attached to a log line
+type Fields map[string]interface{}
+
+// output : the destination, level and sensitive keys shared by a logger and
+// all loggers derived from it
+type output struct {
+	mu        sync.Mutex
+	w         io.Writer
+	level     int32
+	sensitive map[string]bool
+}
+
+// Logger : writes levelled log lines as json, one object per line. Each line
+// holds the time, level and message, along with the logger's fields
+type Logger struct {
+	out     *output
+	fields  Fields
+	secrets []string
+}
+
+// Default : the logger used when no other logger has been set
+var Default = New(os.Stderr)
+
+// New : returns a logger writing lines at info level and above
+func New(w io.Writer) *Logger {
+	return &Logger{
+		out:    &output{w: w, level: int32(INFO)},
+		fields: Fields{},
+	}
+}
+
+// SetLevel : sets the lowest level written, by this logger and all loggers sharing its output
+func (l *Logger) SetLevel(level Level) {
+	atomic.StoreInt32(&l.out.level, int32(level))
+}
+
+// SetSensitiveKeys : sets the keys whose values are redacted on the fields of
+// lines written, by this logger and all loggers sharing its output
+func (l *Logger) SetSensitiveKeys(keys ...string) {
+	sensitive := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		sensitive[k] = true
+	}
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+
+	l.out.sensitive = sensitive
+}
+
+// WithSecrets : returns a logger redacting the given values wherever they
+// appear on a line, such as on the text of errors
+func (l *Logger) WithSecrets(secrets ...string) *Logger {
+	s := append([]string{}, l.secrets...)
+
+	for _, secret := range secrets {
+		if secret != "" {
+			s = append(s, secret)
+		}
+	}
+
+	return &Logger{out: l.out, fields: l.fields, secrets: s}
+}
+
+// With : returns a logger writing the given field on every line
+func (l *Logger) With(key string, value interface{}) *Logger {
+	return l.WithFields(Fields{key: value})
+}
+
+// WithFields : returns a logger writing the given fields on every line
+func (l *Logger) WithFields(fields Fields) *Logger {
+	f := make(Fields, len(l.fields)+len(fields))
+
+	for k, v := range l.fields {
+		f[k] = v
+	}
+
+	for k, v := range fields {
+		f[k] = v
+	}
+
+	return &Logger{out: l.out, fields: f, secrets: l.secrets}
+}
+
+// Debug : writes a line at debug level
+func (l *Logger) Debug(msg string, fields ...Fields) {
+	l.write(DEBUG, msg, fields)
+}
+
+// Info : writes a line at info level
+func (l *Logger) Info(msg string, fields ...Fields) {
+	l.write(INFO, msg, fields)
+}
+
+// Warn : writes a line at warn level
+func (l *Logger) Warn(msg string, fields ...Fields) {
+	l.write(WARN, msg, fields)
+}
+
+// Error : writes a line at error level
+func (l *Logger) Error(msg string, fields ...Fields) {
+	l.write(ERROR, msg, fields)
+}
+
+// Debug : writes a line at debug level on the default logger
+func Debug(msg string, fields ...Fields) {
+	Default.write(DEBUG, msg, fields)
+}
+
+// Info : writes a line at info level on the default logger
+func Info(msg string, fields ...Fields) {
+	Default.write(INFO, msg, fields)
+}
+
+// Warn : writes a line at warn level on the default logger
+func Warn(msg string, fields ...Fields) {
+	Default.write(WARN, msg, fields)
+}
+
+// Error : writes a line at error level on the default logger
+func Error(msg string, fields ...Fields) {
+	Default.write(ERROR, msg, fields)
+}
+
+func (l *Logger) write(level Level, msg string, extra []Fields) {
+	if int32(level) < atomic.LoadInt32(&l.out.level) {
+		return
+	}
+
+	fields := l.fields
+	if len(extra) > 0 {
+		fields = l.WithFields(merge(extra)).fields
+	}
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+
+	line := encode(time.Now().UTC(), level, l.mask(msg), l.redact(fields))
+
+	_, _ = l.out.w.Write(line)
+}
+
+func merge(extra []Fields) Fields {
+	f := Fields{}
+
+	for _, fields := range extra {
+		for k, v := range fields {
+			f[k] = v
+		}
+	}
+
+	return f
+}
+
+// redact : returns the fields with the values of sensitive keys and any
+// secrets replaced. Values are encoded as json first, so sensitive keys are
+// found on nested values too
+func (l *Logger) redact(fields Fields) Fields {
+	r := make(Fields, len(fields))
+
+	for k, v := range fields {
+		r[k] = l.redactValue(decoded(v))
+
+		if l.out.sensitive[k] && r[k] != nil && r[k] != "" {
+			r[k] = REDACTED
+		}
+	}
+
+	return r
+}
+
+func (l *Logger) redactValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		return l.mask(x)
+	case []interface{}:
+		for i := range x {
+			x[i] = l.redactValue(x[i])
+		}
+	case map[string]interface{}:
+		for k := range x {
+			if l.out.sensitive[k] && x[k] != nil && x[k] != "" {
+				x[k] = REDACTED
+				continue
+			}
+			x[k] = l.redactValue(x[k])
+		}
+	}
+
+	return v
+}
+
+// mask : replaces any secrets on a string
+func (l *Logger) mask(s string) string {
+	for _, secret := range l.secrets {
+		s = strings.Replace(s, secret, REDACTED, -1)
+	}
+
+	return s
+}
+
+// decoded : returns a value as it would be decoded from json. Errors are
+// written as their message, while values that can not be encoded are written
+// as they would be printed
+func decoded(v interface{}) interface{} {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	var d interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if dec.Decode(&d) != nil {
+		return fmt.Sprint(v)
+	}
+
+	return d
+}
+
+// encode : writes the time, level and message first, followed by the fields sorted by key
+func encode(t time.Time, level Level, msg string, fields Fields) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(`{"time":`)
+	buf.Write(value(t.Format(time.RFC3339Nano)))
+	buf.WriteString(`,"level":`)
+	buf.Write(value(level.String()))
+	buf.WriteString(`,"msg":`)
+	buf.Write(value(msg))
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k == "time" || k == "level" || k == "msg" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		buf.WriteString(",")
+		buf.Write(value(k))
+		buf.WriteString(":")
+		buf.Write(value(fields[k]))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// value : encodes a field value as json
+func value(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+
+	return data
+}
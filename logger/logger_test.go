@@ -0,0 +1,85 @@
+package logger
+
+// Basic imports
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// LoggerTestSuite : Test suite for logger
+type LoggerTestSuite struct {
+	suite.Suite
+}
+
+// TestFields : Testing lines are written as json with the logger's fields
+func (suite *LoggerTestSuite) TestFields() {
+	var buf bytes.Buffer
+	var line map[string]interface{}
+
+	l := New(&buf).With("id", "test-id").WithFields(Fields{"subject": "mapping.get.create"})
+	l.Info("request completed", Fields{"error": errors.New("failed")})
+
+	suite.True(strings.HasPrefix(buf.String(), `{"time":`))
+	suite.Nil(json.Unmarshal(buf.Bytes(), &line))
+	suite.Equal("info", line["level"])
+	suite.Equal("request completed", line["msg"])
+	suite.Equal("test-id", line["id"])
+	suite.Equal("mapping.get.create", line["subject"])
+	suite.Equal("failed", line["error"])
+}
+
+// TestRedact : Testing sensitive fields and secrets are not written
+func (suite *LoggerTestSuite) TestRedact() {
+	var buf bytes.Buffer
+	var line map[string]interface{}
+
+	l := New(&buf)
+	l.SetSensitiveKeys("password")
+
+	s := l.WithSecrets("s3cr3t").With("id", "test-id")
+	s.Error("request failed", Fields{
+		"error":    errors.New("invalid credentials: s3cr3t"),
+		"password": "s3cr3t",
+		"fields":   map[string]interface{}{"password": "other", "name": "db"},
+	})
+
+	suite.NotContains(buf.String(), "s3cr3t")
+	suite.NotContains(buf.String(), "other")
+	suite.Nil(json.Unmarshal(buf.Bytes(), &line))
+	suite.Equal("invalid credentials: "+REDACTED, line["error"])
+	suite.Equal(REDACTED, line["password"])
+	suite.Equal(map[string]interface{}{"password": REDACTED, "name": "db"}, line["fields"])
+	suite.Equal("test-id", line["id"])
+}
+
+// TestLevel : Testing lines below the logger's level are not written
+func (suite *LoggerTestSuite) TestLevel() {
+	var buf bytes.Buffer
+
+	l := New(&buf)
+	c := l.With("id", "test-id")
+
+	c.Debug("hidden")
+	suite.Equal(0, buf.Len())
+
+	l.SetLevel(DEBUG)
+	c.Debug("shown")
+	suite.Contains(buf.String(), `"level":"debug","msg":"shown"`)
+
+	level, err := ParseLevel("WARN")
+	suite.Nil(err)
+	suite.Equal(WARN, level)
+
+	_, err = ParseLevel("verbose")
+	suite.NotNil(err)
+}
+
+// TestLoggerTestSuite : Test suite for logger
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggerTestSuite))
+}
@@ -5,15 +5,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"os"
-	"runtime"
 	"strings"
 	"time"
 
-	"github.com/ernestio/definition-mapper/build"
+	"github.com/ernestio/definition-mapper/cost"
 	"github.com/ernestio/definition-mapper/handlers"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/logger"
+	"github.com/ernestio/definition-mapper/plan"
+	"github.com/ernestio/definition-mapper/policy"
 	"github.com/ernestio/definition-mapper/request"
+	"github.com/ernestio/definition-mapper/terraform"
 	ecc "github.com/ernestio/ernest-config-client"
 	"github.com/nats-io/go-nats"
 	"github.com/r3labs/akira"
@@ -22,89 +27,372 @@ import (
 
 var n akira.Connector
 
+// version : the mapper version, set at build time
+var version = "dev"
+
+// OPERATIONS : the mapping operations supported by the mapper, cost estimates and terraform exports are limited to aws and azure
+var OPERATIONS = []string{"create", "update", "delete", "import", "diff", "validate", "plan", "cost", "drift", "terraform"}
+
 // StartMappingHandlers : start the primary mapping handlers
 func StartMappingHandlers() {
-	_, _ = n.Subscribe("mapping.get.*", func(msg *nats.Msg) {
-		var r request.Request
-		var g *graph.Graph
-		var data []byte
-		var err error
+	subscribe("mapping.get.*", mappingMessage)
+	subscribe("mapping.get.schema.*", schemaMessage)
+}
 
-		defer response(msg.Reply, &data, &err)
+// mappingMessage : handles a mapping request
+func mappingMessage(ctx context.Context, msg *nats.Msg) (provider string, data []byte, err error) {
+	var r request.Request
 
+	l := logger.Default.With("subject", msg.Subject)
+	defer func(start time.Time) { logResult(l, start, &err) }(time.Now())
+
+	// requests that don't depend on a provider can be sent without a body
+	if len(msg.Data) > 0 {
 		err = json.Unmarshal(msg.Data, &r)
 		if err != nil {
-			return
+			return "", nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, err.Error())
 		}
+	}
 
-		parts := strings.Split(msg.Subject, ".")
-		switch parts[2] {
-		case "create":
-			g, err = handlers.Create(&r)
-		case "update":
-			g, err = handlers.Update(&r)
-		case "delete":
-			g, err = handlers.Delete(&r)
-		case "import":
-			g, err = handlers.Import(&r)
-		case "diff":
-			g, err = handlers.Diff(&r)
-		}
+	if r.CorrelationID == "" {
+		r.CorrelationID = request.NewCorrelationID()
+	}
 
-		if err != nil {
-			return
-		}
+	l = r.Logger().WithFields(logger.Fields{"subject": msg.Subject, "provider": r.Provider()})
+	r.Log = l
+	r.Context = ctx
+
+	parts := strings.Split(msg.Subject, ".")
+
+	h := mappingHandler(parts[2])
+	if h == nil {
+		return r.Provider(), nil, libmapper.NewError(libmapper.ErrCodeUnsupportedOperation, "unsupported mapping operation: "+parts[2])
+	}
+
+	data, err = h(&r)
 
-		data, err = g.ToJSON()
-	})
+	return r.Provider(), data, err
 }
 
-// StartSecondaryHandlers : start secondary handlers
-func StartSecondaryHandlers() {
-	_, _ = n.Subscribe("build.import.done", func(msg *nats.Msg) {
-		var ig map[string]interface{}
-		var b *build.Build
-		var data []byte
-		var err error
+// schemaMessage : handles a schema request for the provider named by the subject
+func schemaMessage(ctx context.Context, msg *nats.Msg) (provider string, data []byte, err error) {
+	l := logger.Default.With("subject", msg.Subject)
+
+	defer logResult(l, time.Now(), &err)
+
+	parts := strings.Split(msg.Subject, ".")
+
+	s, err := handlers.Schema(parts[3])
+	if err != nil {
+		return parts[3], nil, err
+	}
+
+	data, err = json.Marshal(s)
 
-		defer response(msg.Reply, &data, &err)
+	return parts[3], data, err
+}
+
+// mappingOperation : handles a mapping request, returning the encoded response
+type mappingOperation func(*request.Request) ([]byte, error)
+
+// mappingHandler : returns the handler responsible for a mapping operation
+func mappingHandler(op string) mappingOperation {
+	switch op {
+	case "create":
+		return graphOperation(op, handlers.Create, libmapper.RedactChangelogs)
+	case "update":
+		return graphOperation(op, handlers.Update, libmapper.RedactChangelogs)
+	case "delete":
+		return graphOperation(op, handlers.Delete, libmapper.RedactChangelogs)
+	case "import":
+		return graphOperation(op, handlers.Import, libmapper.RedactChangelogs)
+	case "diff":
+		return graphOperation(op, handlers.Diff, libmapper.RedactGraph)
+	case "validate":
+		return validateOperation
+	case "plan":
+		return planOperation
+	case "cost":
+		return costOperation
+	case "drift":
+		return driftOperation
+	case "terraform":
+		return terraformOperation
+	case "capabilities":
+		return capabilitiesOperation
+	}
+
+	return nil
+}
 
-		err = json.Unmarshal(msg.Data, &ig)
+// graphOperation : wraps a handler that returns a graph, encoding it so any
+// sensitive values shown to users are redacted. Graphs passed on to be built
+// keep their component values
+func graphOperation(op string, h func(*request.Request) (*graph.Graph, error), encode func(*graph.Graph) ([]byte, error)) mappingOperation {
+	return func(r *request.Request) ([]byte, error) {
+		g, err := h(r)
 		if err != nil {
-			return
+			return nil, err
 		}
 
-		b, err = handlers.ImportComplete(ig)
-		if err != nil {
-			return
+		observeGraph(op, r.Provider(), g)
+
+		if op != "create" && op != "update" {
+			return encode(g)
 		}
 
-		data, err = json.Marshal(b)
+		data, err := encode(g)
 		if err != nil {
-			return
+			return nil, err
 		}
 
-		_, err = n.Request("build.set.mapping", data, time.Second*5)
-		if err != nil {
-			return
+		if len(r.Warnings) > 0 {
+			data, err = withValue(data, "_warnings", r.Warnings)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if r.PolicyResult == nil {
+			return data, nil
+		}
+
+		return withValue(data, "_policy", r.PolicyResult)
+	}
+}
+
+func validateOperation(r *request.Request) ([]byte, error) {
+	v, err := handlers.Validate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+// planOperation : returns the plan along with its output, rendered in the requested format
+func planOperation(r *request.Request) ([]byte, error) {
+	p, err := handlers.Plan(r)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := p.Render(r.Format)
+	if err != nil {
+		return nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, err.Error())
+	}
+
+	return json.Marshal(struct {
+		*plan.Plan
+		Output string `json:"output"`
+	}{p, output})
+}
+
+// costOperation : returns the estimated monthly cost of the request's definition or mapping
+func costOperation(r *request.Request) ([]byte, error) {
+	e, err := handlers.Cost(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(e)
+}
+
+// driftOperation : returns the differences between a stored mapping and an import of its service
+func driftOperation(r *request.Request) ([]byte, error) {
+	d, err := handlers.Drift(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(d)
+}
+
+// terraformOperation : returns the terraform configuration of the request's mapping, along with its resources and imports
+func terraformOperation(r *request.Request) ([]byte, error) {
+	e, err := handlers.Terraform(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		*terraform.Export
+		HCL string `json:"hcl"`
+	}{e, e.HCL()})
+}
+
+// capabilitiesOperation : returns what the running mapper supports
+func capabilitiesOperation(r *request.Request) ([]byte, error) {
+	c, err := handlers.Capabilities(version, OPERATIONS)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(c)
+}
+
+// StartSecondaryHandlers : start secondary handlers
+func StartSecondaryHandlers() {
+	subscribe("build.import.done", importDoneMessage)
+}
+
+// importDoneMessage : converts a completed import graph to a build, storing its mapping and definition
+func importDoneMessage(ctx context.Context, msg *nats.Msg) (provider string, data []byte, err error) {
+	var ig map[string]interface{}
+
+	l := logger.Default.With("subject", msg.Subject)
+
+	defer logResult(l, time.Now(), &err)
+
+	err = json.Unmarshal(msg.Data, &ig)
+	if err != nil {
+		return "", nil, libmapper.NewError(libmapper.ErrCodeInvalidRequest, err.Error())
+	}
+
+	err = loadMergeTarget(ig)
+	if err != nil {
+		return "", nil, err
+	}
+
+	b, err := handlers.ImportComplete(ig)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, w := range b.Warnings {
+		l.Warn(w.Message, logger.Fields{"component_id": w.ComponentID})
+	}
+
+	if b.Merge != nil {
+		for _, c := range b.Merge.Conflicts {
+			l.Warn("imported entry conflicts with the existing definition", logger.Fields{"key": c.Key, "name": c.Name, "fields": c.Fields})
 		}
+	}
+
+	data, err = json.Marshal(b)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, err = n.Request("build.set.mapping", data, time.Second*5)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, err = n.Request("build.set.definition", data, time.Second*5)
+	if err != nil {
+		return "", nil, err
+	}
 
-		_, err = n.Request("build.set.definition", data, time.Second*5)
+	return "", []byte(`{"status": "success"}`), nil
+}
+
+// loadMergeTarget : loads the definition and mapping of the build a completed
+// import is merged into from the build store, setting them on the import graph
+func loadMergeTarget(ig map[string]interface{}) error {
+	id := handlers.MergeBuild(ig)
+	if id == "" {
+		return nil
+	}
+
+	query, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	msg, err := n.Request("build.get.definition", query, time.Second*5)
+	if err != nil {
+		return err
+	}
+
+	var b struct {
+		Definition string `json:"definition"`
+	}
+
+	err = json.Unmarshal(msg.Data, &b)
+	if err != nil {
+		return err
+	}
+
+	msg, err = n.Request("build.get.mapping", query, time.Second*5)
+	if err != nil {
+		return err
+	}
+
+	var mapping map[string]interface{}
+
+	err = json.Unmarshal(msg.Data, &mapping)
+	if err != nil {
+		return err
+	}
+
+	handlers.SetMergeTarget(ig, b.Definition, mapping)
+
+	return nil
+}
+
+// configureLogging : sets the lowest level of log lines written from
+// LOG_LEVEL, and the keys whose values are never written
+func configureLogging() {
+	logger.Default.SetSensitiveKeys(libmapper.SENSITIVEKEYS...)
+
+	if l := os.Getenv("LOG_LEVEL"); l != "" {
+		level, err := logger.ParseLevel(l)
 		if err != nil {
+			logger.Warn("invalid LOG_LEVEL value, using the default", logger.Fields{"value": l})
 			return
 		}
 
-		data = []byte(`{"status": "success"}`)
-	})
+		logger.Default.SetLevel(level)
+	}
+}
+
+// configurePolicies : loads the rules set by POLICIES_FILE once on startup,
+// exiting if they can not be loaded, as requests would otherwise be mapped
+// without them
+func configurePolicies() {
+	err := policy.LoadDefaultRules()
+	if err != nil {
+		logger.Error("could not load the policy rules", logger.Fields{"path": os.Getenv(policy.POLICIESFILE), "error": err})
+		os.Exit(1)
+	}
+}
+
+// configurePrices : loads the price table set by PRICES_FILE once on startup,
+// keeping the bundled table if it can not be loaded
+func configurePrices() {
+	err := cost.LoadDefaultPrices()
+	if err != nil {
+		logger.Error("could not load the price table, using the bundled one", logger.Fields{"path": os.Getenv(cost.PRICESFILE), "error": err})
+	}
 }
 
 func setup() {
 	n = ecc.NewConfig(os.Getenv("NATS_URI")).Nats()
+	configureWorkers()
+	configureShutdown()
 }
 
 func main() {
+	configureLogging()
+	configurePolicies()
+	configurePrices()
+
+	if len(os.Args) > 1 {
+		os.Exit(RunCLI(os.Args[1:], os.Stdout))
+	}
+
 	setup()
 	StartMappingHandlers()
 	StartSecondaryHandlers()
-	runtime.Goexit()
+
+	if addr := os.Getenv("HTTP_ADDR"); addr != "" {
+		StartHTTPServer(addr)
+	}
+
+	if addr := os.Getenv("METRICS_ADDR"); addr != "" {
+		StartMetricsServer(addr)
+	}
+
+	waitForShutdown()
 }
@@ -0,0 +1,236 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package merge
+
+import (
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// document : the lines of a block style yaml document, used to add entries
+// to a definition without changing any of its existing text
+type document struct {
+	lines []string
+}
+
+// region : the lines of a mapping, whose keys start at the given column
+type region struct {
+	start int
+	end   int
+	col   int
+}
+
+func newDocument(data []byte) *document {
+	return &document{lines: strings.Split(string(data), "\n")}
+}
+
+func (d *document) bytes() []byte {
+	return []byte(strings.Join(d.lines, "\n"))
+}
+
+// insert : appends entries to the list at the insertion's path, returning
+// false if the list could not be found in the document's text
+func (d *document) insert(in insertion) bool {
+	r := region{start: 0, end: len(d.lines), col: 0}
+
+	for i := 0; i < len(in.path); i++ {
+		key, ok := in.path[i].(string)
+		if !ok {
+			return false
+		}
+
+		kl := d.findKey(r, key)
+		if kl < 0 {
+			// the list is not set on the existing entry, so it is added along with its key
+			return i == len(in.path)-1 && d.add(d.last(r, r.start), yaml.MapSlice{{Key: key, Value: in.items}}, r.col)
+		}
+
+		value := d.value(kl, r.col)
+		if value != "" && value != "[]" {
+			return false
+		}
+
+		block := region{start: kl + 1, end: d.blockEnd(kl, r.col), col: r.col}
+
+		if i == len(in.path)-1 {
+			if value == "[]" {
+				d.lines[kl] = d.lines[kl][:strings.Index(d.lines[kl], ":")+1]
+			}
+
+			dc, ok := d.dashColumn(block)
+			if !ok {
+				dc = r.col + 2
+			}
+
+			return d.add(d.last(block, kl), in.items, dc)
+		}
+
+		i++
+		idx, ok := in.path[i].(int)
+		if !ok {
+			return false
+		}
+
+		r, ok = d.item(block, idx)
+		if !ok {
+			return false
+		}
+	}
+
+	return false
+}
+
+// add : renders a value below the given line
+func (d *document) add(after int, v interface{}, indent int) bool {
+	text, err := render(v, indent)
+	if err != nil {
+		return false
+	}
+
+	lines := append([]string{}, d.lines[:after+1]...)
+	lines = append(lines, strings.Split(text, "\n")...)
+	d.lines = append(lines, d.lines[after+1:]...)
+
+	return true
+}
+
+// findKey : returns the line setting a key of a mapping, or -1 if it is not set
+func (d *document) findKey(r region, key string) int {
+	for i := r.start; i < r.end; i++ {
+		if !d.significant(i) {
+			continue
+		}
+
+		k, ok := d.keyAt(i, r.col)
+		if ok && k == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// keyAt : returns the mapping key starting at the given column of a line.
+// The column may follow the dash of a list entry
+func (d *document) keyAt(i, col int) (string, bool) {
+	line := d.lines[i]
+	if len(line) <= col {
+		return "", false
+	}
+
+	if strings.TrimSpace(strings.Replace(line[:col], "-", " ", -1)) != "" {
+		return "", false
+	}
+
+	s := line[col:]
+	if s[0] == ' ' || s[0] == '-' || s[0] == '#' {
+		return "", false
+	}
+
+	idx := strings.Index(s, ":")
+	if idx < 0 || (idx+1 < len(s) && s[idx+1] != ' ') {
+		return "", false
+	}
+
+	return strings.Trim(s[:idx], `"'`), true
+}
+
+// value : returns the inline value of a key, without any comment
+func (d *document) value(i, col int) string {
+	line := d.lines[i][col:]
+	value := line[strings.Index(line, ":")+1:]
+
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = value[:idx]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// blockEnd : returns the line following the value of a key set at the given column.
+// Lists may start at the same column as their key
+func (d *document) blockEnd(kl, col int) int {
+	for i := kl + 1; i < len(d.lines); i++ {
+		if !d.significant(i) {
+			continue
+		}
+
+		indent := indentOf(d.lines[i])
+		if indent < col || (indent == col && !strings.HasPrefix(strings.TrimSpace(d.lines[i]), "-")) {
+			return i
+		}
+	}
+
+	return len(d.lines)
+}
+
+// dashColumn : returns the column of the dashes of a list's entries
+func (d *document) dashColumn(r region) (int, bool) {
+	for i := r.start; i < r.end; i++ {
+		if !d.significant(i) {
+			continue
+		}
+
+		if strings.HasPrefix(strings.TrimSpace(d.lines[i]), "-") {
+			return indentOf(d.lines[i]), true
+		}
+
+		return 0, false
+	}
+
+	return 0, false
+}
+
+// item : returns the mapping of the list entry at the given index
+func (d *document) item(r region, idx int) (region, bool) {
+	dc, ok := d.dashColumn(r)
+	if !ok {
+		return region{}, false
+	}
+
+	var starts []int
+
+	for i := r.start; i < r.end; i++ {
+		if d.significant(i) && indentOf(d.lines[i]) == dc && strings.HasPrefix(strings.TrimSpace(d.lines[i]), "-") {
+			starts = append(starts, i)
+		}
+	}
+
+	if idx >= len(starts) {
+		return region{}, false
+	}
+
+	end := r.end
+	if idx+1 < len(starts) {
+		end = starts[idx+1]
+	}
+
+	line := d.lines[starts[idx]]
+	col := dc + 1 + indentOf(line[dc+1:])
+
+	return region{start: starts[idx], end: end, col: col}, true
+}
+
+// last : returns the last significant line of a region, or the given line if there is none
+func (d *document) last(r region, fallback int) int {
+	for i := r.end - 1; i >= r.start; i-- {
+		if d.significant(i) {
+			return i
+		}
+	}
+
+	return fallback
+}
+
+// significant : returns false for blank lines, comments and document markers
+func (d *document) significant(i int) bool {
+	s := strings.TrimSpace(d.lines[i])
+	return s != "" && !strings.HasPrefix(s, "#") && s != "---"
+}
+
+func indentOf(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " "))
+}
@@ -0,0 +1,32 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package merge
+
+import (
+	"github.com/r3labs/graph"
+)
+
+// Mappings : merges the components of an imported mapping into an existing
+// one, returning the ids of the components added. As with definitions,
+// existing components are kept as they are, so the mapping stays in step
+// with the merged definition
+func Mappings(existing, imported *graph.Graph) ([]string, error) {
+	added := []string{}
+
+	for _, c := range imported.Components {
+		if existing.HasComponent(c.GetID()) {
+			continue
+		}
+
+		err := existing.AddComponent(c)
+		if err != nil {
+			return nil, err
+		}
+
+		added = append(added, c.GetID())
+	}
+
+	return added, nil
+}
@@ -0,0 +1,304 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package merge
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Report : the outcome of merging an imported definition into an existing one
+type Report struct {
+	Added     []Entry    `json:"added"`
+	Conflicts []Conflict `json:"conflicts"`
+	Rewritten bool       `json:"rewritten,omitempty"`
+}
+
+// Entry : a definition entry, identified by the key of the list it belongs to and its name
+type Entry struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Conflict : an existing entry whose properties differ from the imported entry of
+// the same name. Entries without a name are the top level fields of the definition
+type Conflict struct {
+	Key    string   `json:"key,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Fields []string `json:"fields"`
+}
+
+// insertion : new entries to be appended to a list of the existing definition
+type insertion struct {
+	path  []interface{}
+	items []interface{}
+}
+
+// Definitions : merges an imported definition into an existing definition.
+// Entries are lists of mappings identified by their name, i.e. instances.
+// Entries that are not part of the existing definition are appended to it,
+// including those nested within existing entries, such as the virtual
+// machines of an azure resource group. Existing entries are never changed,
+// but are reported as conflicting if their properties differ from those
+// imported. The existing definition's text is kept as it is, unless new
+// entries cannot be added to it, i.e. as it uses flow style lists, in which
+// case the merged definition is rendered again and reported as rewritten
+func Definitions(existing, imported []byte) ([]byte, *Report, error) {
+	var ed, id yaml.MapSlice
+
+	err := yaml.Unmarshal(existing, &ed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load existing definition: %s", err.Error())
+	}
+
+	err = yaml.Unmarshal(imported, &id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load imported definition: %s", err.Error())
+	}
+
+	r := Report{
+		Added:     []Entry{},
+		Conflicts: []Conflict{},
+	}
+
+	var inserts []insertion
+
+	fields := mergeMaps(ed, id, nil, "", &r, &inserts)
+	if len(fields) > 0 {
+		r.Conflicts = append(r.Conflicts, Conflict{Fields: fields})
+	}
+
+	sort.SliceStable(r.Conflicts, func(i, j int) bool {
+		return r.Conflicts[i].Key < r.Conflicts[j].Key
+	})
+
+	if len(inserts) < 1 {
+		return existing, &r, nil
+	}
+
+	doc := newDocument(existing)
+
+	for _, in := range inserts {
+		if !doc.insert(in) {
+			r.Rewritten = true
+			break
+		}
+	}
+
+	if !r.Rewritten {
+		return doc.bytes(), &r, nil
+	}
+
+	// the existing text cannot be added to, so the merged definition is rendered instead
+	for _, in := range inserts {
+		ed = apply(ed, in.path, in.items).(yaml.MapSlice)
+	}
+
+	data, err := yaml.Marshal(ed)
+
+	return data, &r, err
+}
+
+// mergeMaps : merges the fields of an imported mapping into an existing one,
+// returning the fields that conflict
+func mergeMaps(em, im yaml.MapSlice, path []interface{}, key string, r *Report, inserts *[]insertion) []string {
+	var conflicts []string
+
+	for _, item := range im {
+		k := fmt.Sprint(item.Key)
+		ev, ok := get(em, k)
+
+		switch {
+		case isNamedList(item.Value) && (!ok || ev == nil || isNamedList(ev) || isEmptyList(ev)):
+			el, _ := ev.([]interface{})
+			mergeLists(el, item.Value.([]interface{}), append(clone(path), k), join(key, k), r, inserts)
+		case !ok:
+			if !isZero(item.Value) {
+				conflicts = append(conflicts, k)
+			}
+		case !equal(ev, item.Value):
+			conflicts = append(conflicts, k)
+		}
+	}
+
+	return conflicts
+}
+
+// mergeLists : merges the entries of an imported list into an existing list,
+// matching entries by name
+func mergeLists(el, il []interface{}, path []interface{}, key string, r *Report, inserts *[]insertion) {
+	var added []interface{}
+
+	for _, ie := range il {
+		im := ie.(yaml.MapSlice)
+		name := entryName(im)
+
+		i := find(el, name)
+		if i < 0 {
+			added = append(added, im)
+			r.Added = append(r.Added, Entry{Key: key, Name: name})
+			continue
+		}
+
+		fields := mergeMaps(el[i].(yaml.MapSlice), im, append(clone(path), i), key+"["+name+"]", r, inserts)
+		if len(fields) > 0 {
+			r.Conflicts = append(r.Conflicts, Conflict{Key: key, Name: name, Fields: fields})
+		}
+	}
+
+	if len(added) > 0 {
+		*inserts = append(*inserts, insertion{path: path, items: added})
+	}
+}
+
+// apply : appends items to the list at the given path of a definition
+func apply(v interface{}, path []interface{}, items []interface{}) interface{} {
+	if len(path) < 1 {
+		l, _ := v.([]interface{})
+		return append(l, items...)
+	}
+
+	switch p := path[0].(type) {
+	case string:
+		m, _ := v.(yaml.MapSlice)
+		for i := range m {
+			if fmt.Sprint(m[i].Key) == p {
+				m[i].Value = apply(m[i].Value, path[1:], items)
+				return m
+			}
+		}
+		return append(m, yaml.MapItem{Key: p, Value: apply(nil, path[1:], items)})
+	case int:
+		l := v.([]interface{})
+		l[p] = apply(l[p], path[1:], items)
+		return l
+	}
+
+	return v
+}
+
+func get(m yaml.MapSlice, key string) (interface{}, bool) {
+	for _, item := range m {
+		if fmt.Sprint(item.Key) == key {
+			return item.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func find(l []interface{}, name string) int {
+	for i, e := range l {
+		m, ok := e.(yaml.MapSlice)
+		if ok && entryName(m) == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func entryName(m yaml.MapSlice) string {
+	name, _ := get(m, "name")
+	return fmt.Sprint(name)
+}
+
+// isNamedList : returns true if the value is a list of mappings with a name
+func isNamedList(v interface{}) bool {
+	l, ok := v.([]interface{})
+	if !ok || len(l) < 1 {
+		return false
+	}
+
+	for _, e := range l {
+		m, ok := e.(yaml.MapSlice)
+		if !ok {
+			return false
+		}
+
+		if _, ok := get(m, "name"); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEmptyList(v interface{}) bool {
+	l, ok := v.([]interface{})
+	return ok && len(l) < 1
+}
+
+func isZero(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		return rv.Len() == 0
+	}
+
+	return reflect.DeepEqual(v, reflect.Zero(rv.Type()).Interface())
+}
+
+// equal : compares two values, ignoring the order of mapping keys
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case yaml.MapSlice:
+		m := make(map[string]interface{}, len(x))
+		for _, item := range x {
+			m[fmt.Sprint(item.Key)] = normalize(item.Value)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i := range x {
+			l[i] = normalize(x[i])
+		}
+		return l
+	}
+
+	return v
+}
+
+func clone(path []interface{}) []interface{} {
+	return append([]interface{}{}, path...)
+}
+
+func join(key, k string) string {
+	if key == "" {
+		return k
+	}
+
+	return key + "." + k
+}
+
+// render : renders a value as yaml, indenting every line
+func render(v interface{}, indent int) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := strings.Repeat(" ", indent)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,135 @@
+package merge_test
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/merge"
+	"github.com/stretchr/testify/suite"
+)
+
+// MergeTestSuite : Test suite for merging imported definitions
+type MergeTestSuite struct {
+	suite.Suite
+}
+
+const existing = `---
+# payments service
+name: payments
+project: acme
+
+instances:
+  - name: web
+    type: t2.micro   # sized for staging
+    count: 2
+
+  - name: worker
+    type: t2.small
+
+# storage
+ebs_volumes: []
+`
+
+const imported = `name: payments
+project: acme
+instances:
+- name: web
+  type: t2.micro
+  count: 2
+- name: worker
+  type: t2.large
+- name: bastion
+  type: t2.nano
+ebs_volumes:
+- name: data
+  size: 10
+s3_buckets:
+- name: assets
+  acl: private
+`
+
+// TestDefinitions : Testing imported entries are added to the existing text
+func (suite *MergeTestSuite) TestDefinitions() {
+	data, r, err := merge.Definitions([]byte(existing), []byte(imported))
+	suite.Nil(err)
+	suite.False(r.Rewritten)
+	suite.Equal(`---
+# payments service
+name: payments
+project: acme
+
+instances:
+  - name: web
+    type: t2.micro   # sized for staging
+    count: 2
+
+  - name: worker
+    type: t2.small
+  - name: bastion
+    type: t2.nano
+
+# storage
+ebs_volumes:
+  - name: data
+    size: 10
+s3_buckets:
+- name: assets
+  acl: private
+`, string(data))
+
+	suite.Equal([]merge.Entry{{Key: "instances", Name: "bastion"}, {Key: "ebs_volumes", Name: "data"}, {Key: "s3_buckets", Name: "assets"}}, r.Added)
+	suite.Equal([]merge.Conflict{{Key: "instances", Name: "worker", Fields: []string{"type"}}}, r.Conflicts)
+}
+
+// TestNestedDefinitions : Testing imported entries are added within existing entries
+func (suite *MergeTestSuite) TestNestedDefinitions() {
+	data, r, err := merge.Definitions([]byte(`name: web
+project: acme
+resource_groups:
+  - name: rg-1
+    location: westeurope
+    virtual_machines:
+      - name: vm-1
+`), []byte(`name: web
+project: acme
+resource_groups:
+- name: rg-1
+  location: westeurope
+  virtual_machines:
+  - name: vm-1
+  - name: vm-2
+  storage_accounts:
+  - name: sa-1
+- name: rg-2
+  location: westeurope
+`))
+	suite.Nil(err)
+	suite.Len(r.Conflicts, 0)
+	suite.Equal(`name: web
+project: acme
+resource_groups:
+  - name: rg-1
+    location: westeurope
+    virtual_machines:
+      - name: vm-1
+      - name: vm-2
+    storage_accounts:
+    - name: sa-1
+  - name: rg-2
+    location: westeurope
+`, string(data))
+
+	suite.Equal("resource_groups[rg-1].virtual_machines", r.Added[0].Key)
+}
+
+// TestRewritten : Testing definitions using flow style lists are rendered again
+func (suite *MergeTestSuite) TestRewritten() {
+	data, r, err := merge.Definitions([]byte("name: web\nproject: acme\ninstances: [{name: web}]\n"), []byte("name: web\nproject: acme\ninstances:\n- name: db\n"))
+	suite.Nil(err)
+	suite.True(r.Rewritten)
+	suite.Equal("name: web\nproject: acme\ninstances:\n- name: web\n- name: db\n", string(data))
+}
+
+// TestMergeTestSuite : Test suite for merging imported definitions
+func TestMergeTestSuite(t *testing.T) {
+	suite.Run(t, new(MergeTestSuite))
+}
@@ -0,0 +1,54 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// UNKNOWN : the label value of any value that is not known in advance, so
+// requests can not create an unbounded number of series
+const UNKNOWN = "unknown"
+
+// DURATIONBUCKETS : histogram buckets for request durations, in seconds
+var DURATIONBUCKETS = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// SIZEBUCKETS : histogram buckets for graph sizes, in components
+var SIZEBUCKETS = []float64{0, 1, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+// NewCounter : returns a new counter partitioned by labels, registered on the default registry
+func NewCounter(name, help string, labels ...string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	prometheus.MustRegister(c)
+
+	return c
+}
+
+// NewHistogram : returns a new histogram partitioned by labels, registered on the default registry
+func NewHistogram(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	prometheus.MustRegister(h)
+
+	return h
+}
+
+// Handler : returns a handler exposing the default registry
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Label : returns a label value if it is one of the known values, or UNKNOWN otherwise
+func Label(value string, known ...string) string {
+	for _, k := range known {
+		if value == k {
+			return value
+		}
+	}
+
+	return UNKNOWN
+}
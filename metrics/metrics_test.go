@@ -0,0 +1,37 @@
+package metrics
+
+// Basic imports
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// MetricsTestSuite : Test suite for metrics
+type MetricsTestSuite struct {
+	suite.Suite
+}
+
+// TestLabel : Testing unknown label values are bounded
+func (suite *MetricsTestSuite) TestLabel() {
+	suite.Equal("create", Label("create", "create", "update"))
+	suite.Equal(UNKNOWN, Label("anything", "create", "update"))
+	suite.Equal(UNKNOWN, Label("create"))
+}
+
+// TestHandler : Testing registered metrics are exposed
+func (suite *MetricsTestSuite) TestHandler() {
+	c := NewCounter("test_requests_total", "Test requests.", "subject")
+	c.WithLabelValues("mapping.get.create").Inc()
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	suite.Contains(w.Body.String(), `test_requests_total{subject="mapping.get.create"} 1`)
+}
+
+// TestMetricsTestSuite : Test suite for metrics
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
@@ -0,0 +1,247 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package plan
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/cost"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/policy"
+	"github.com/r3labs/graph"
+)
+
+const (
+	// ACTIONCREATE : the component will be created
+	ACTIONCREATE = "create"
+	// ACTIONUPDATE : the component will be updated in place
+	ACTIONUPDATE = "update"
+	// ACTIONREPLACE : the component will be deleted and created again, as an immutable field has changed
+	ACTIONREPLACE = "replace"
+	// ACTIONDELETE : the component will be deleted
+	ACTIONDELETE = "delete"
+)
+
+// ACTIONS : all plan actions, in the order they are shown
+var ACTIONS = []string{ACTIONCREATE, ACTIONUPDATE, ACTIONREPLACE, ACTIONDELETE}
+
+// Plan : the changes needed to move a service from one mapping to another
+type Plan struct {
+	Summary map[string]int `json:"summary"`
+	Changes []Change       `json:"changes"`
+	Cost    *cost.Delta    `json:"cost,omitempty"`
+	Policy  *policy.Result `json:"policy,omitempty"`
+}
+
+// Change : a change to a single component
+type Change struct {
+	Action        string        `json:"action"`
+	ComponentID   string        `json:"component_id"`
+	ComponentType string        `json:"component_type"`
+	Name          string        `json:"name"`
+	Fields        []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange : the before and after value of a changed field
+type FieldChange struct {
+	Field     string      `json:"field"`
+	From      interface{} `json:"from"`
+	To        interface{} `json:"to"`
+	Immutable bool        `json:"immutable,omitempty"`
+	Sensitive bool        `json:"sensitive,omitempty"`
+}
+
+// New : builds a plan of the changes needed to move from one graph to another.
+// Field changes are taken from each component's changelog, while changes to
+// immutable fields mark the component for replacement. The values of sensitive
+// fields are redacted
+func New(from, to *graph.Graph) (*Plan, error) {
+	p := Plan{
+		Summary: make(map[string]int),
+		Changes: []Change{},
+	}
+
+	for _, action := range ACTIONS {
+		p.Summary[action] = 0
+	}
+
+	for _, c := range to.Components {
+		if c.GetType() == "credentials" {
+			continue
+		}
+
+		oc := from.Component(c.GetID())
+		if oc == nil {
+			p.add(change(ACTIONCREATE, c, nil))
+			continue
+		}
+
+		fields, err := FieldChanges(oc, c)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields) < 1 {
+			continue
+		}
+
+		action := ACTIONUPDATE
+		for _, f := range fields {
+			if f.Immutable {
+				action = ACTIONREPLACE
+			}
+		}
+
+		p.add(change(action, c, fields))
+	}
+
+	for _, c := range from.Components {
+		if c.GetType() == "credentials" {
+			continue
+		}
+
+		if to.Component(c.GetID()) == nil {
+			p.add(change(ACTIONDELETE, c, nil))
+		}
+	}
+
+	sort.SliceStable(p.Changes, func(i, j int) bool {
+		if p.Changes[i].Action != p.Changes[j].Action {
+			return actionIndex(p.Changes[i].Action) < actionIndex(p.Changes[j].Action)
+		}
+		return p.Changes[i].ComponentID < p.Changes[j].ComponentID
+	})
+
+	return &p, nil
+}
+
+// ByAction : returns all changes with the given action
+func (p *Plan) ByAction(action string) []Change {
+	var changes []Change
+
+	for _, c := range p.Changes {
+		if c.Action == action {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+func (p *Plan) add(c Change) {
+	p.Changes = append(p.Changes, c)
+	p.Summary[c.Action]++
+}
+
+func change(action string, c graph.Component, fields []FieldChange) Change {
+	return Change{
+		Action:        action,
+		ComponentID:   c.GetID(),
+		ComponentType: c.GetType(),
+		Name:          c.GetName(),
+		Fields:        fields,
+	}
+}
+
+// FieldChanges : returns the changes to immutable fields, followed by the component's changelog
+func FieldChanges(from, to graph.Component) ([]FieldChange, error) {
+	fields := immutableChanges(from, to)
+
+	immutable := make(map[string]bool)
+	for _, f := range fields {
+		immutable[f.Field] = true
+	}
+
+	cl, err := to.Diff(from)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cl {
+		if len(c.Path) > 0 && immutable[c.Path[0]] {
+			continue
+		}
+
+		fields = append(fields, FieldChange{
+			Field: strings.Join(c.Path, "."),
+			From:  c.From,
+			To:    c.To,
+		})
+	}
+
+	sensitive := libmapper.SensitiveFields(to)
+
+	for i := range fields {
+		if sensitive[strings.Split(fields[i].Field, ".")[0]] {
+			fields[i].From = libmapper.REDACTED
+			fields[i].To = libmapper.REDACTED
+			fields[i].Sensitive = true
+		}
+	}
+
+	return fields, nil
+}
+
+// immutableChanges : returns any set field tagged as immutable that differs between
+// two components. Fields that are not set on the new component are assumed to be
+// populated by the provider, so are not treated as changed
+func immutableChanges(from, to graph.Component) []FieldChange {
+	var fields []FieldChange
+
+	fv := reflect.Indirect(reflect.ValueOf(from))
+	tv := reflect.Indirect(reflect.ValueOf(to))
+
+	if fv.Kind() != reflect.Struct || fv.Type() != tv.Type() {
+		return fields
+	}
+
+	for i := 0; i < tv.NumField(); i++ {
+		tag := strings.Split(tv.Type().Field(i).Tag.Get("diff"), ",")
+		if len(tag) < 2 || tag[0] == "-" || tag[1] != "immutable" {
+			continue
+		}
+
+		f := fv.Field(i).Interface()
+		t := tv.Field(i).Interface()
+
+		if IsZero(tv.Field(i)) || reflect.DeepEqual(f, t) {
+			continue
+		}
+
+		fields = append(fields, FieldChange{
+			Field:     tag[0],
+			From:      f,
+			To:        t,
+			Immutable: true,
+		})
+	}
+
+	return fields
+}
+
+// IsZero : returns true if a field is not set. Empty maps and slices are
+// treated as not set
+func IsZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+func actionIndex(action string) int {
+	for i, a := range ACTIONS {
+		if a == action {
+			return i
+		}
+	}
+
+	return len(ACTIONS)
+}
@@ -0,0 +1,83 @@
+package plan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/ernestio/definition-mapper/plan"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// PlanTestSuite : Test suite for plans
+type PlanTestSuite struct {
+	suite.Suite
+	From *graph.Graph
+	To   *graph.Graph
+}
+
+func volume(name, zone string) *components.EBSVolume {
+	return &components.EBSVolume{
+		ComponentType:    components.TYPEEBSVOLUME,
+		ComponentID:      components.TYPEEBSVOLUME + components.TYPEDELIMITER + name,
+		Name:             name,
+		AvailabilityZone: zone,
+		VolumeType:       "gp2",
+	}
+}
+
+// SetupTest : Setup test suite
+func (suite *PlanTestSuite) SetupTest() {
+	suite.From = graph.New()
+	suite.From.AddComponent(volume("a", "eu-west-1a"))
+	suite.From.AddComponent(volume("b", "eu-west-1a"))
+	suite.From.AddComponent(volume("c", "eu-west-1a"))
+
+	suite.To = graph.New()
+	suite.To.AddComponent(volume("a", "eu-west-1a"))
+	suite.To.AddComponent(volume("b", "eu-west-1b"))
+	suite.To.AddComponent(volume("d", "eu-west-1a"))
+}
+
+// TestNew : Testing plan actions
+func (suite *PlanTestSuite) TestNew() {
+	p, err := plan.New(suite.From, suite.To)
+	suite.Nil(err)
+	suite.Equal(map[string]int{"create": 1, "update": 0, "replace": 1, "delete": 1}, p.Summary)
+	suite.Len(p.Changes, 3)
+	suite.Equal("ebs_volume::d", p.Changes[0].ComponentID)
+	suite.Equal(plan.ACTIONCREATE, p.Changes[0].Action)
+	suite.Equal("ebs_volume::b", p.Changes[1].ComponentID)
+	suite.Equal(plan.ACTIONREPLACE, p.Changes[1].Action)
+	suite.Equal("availability_zone", p.Changes[1].Fields[0].Field)
+	suite.Equal("eu-west-1a", p.Changes[1].Fields[0].From)
+	suite.Equal("eu-west-1b", p.Changes[1].Fields[0].To)
+	suite.True(p.Changes[1].Fields[0].Immutable)
+	suite.Equal("ebs_volume::c", p.Changes[2].ComponentID)
+	suite.Equal(plan.ACTIONDELETE, p.Changes[2].Action)
+}
+
+// TestRender : Testing plan rendering
+func (suite *PlanTestSuite) TestRender() {
+	p, err := plan.New(suite.From, suite.To)
+	suite.Nil(err)
+
+	text, err := p.Render(plan.FORMATTEXT)
+	suite.Nil(err)
+	suite.True(strings.HasPrefix(text, "Plan: 1 to create, 0 to update, 1 to replace, 1 to delete\n"))
+	suite.Contains(text, `availability_zone: "eu-west-1a" => "eu-west-1b" (forces replacement)`)
+
+	md, err := p.Render(plan.FORMATMARKDOWN)
+	suite.Nil(err)
+	suite.Contains(md, "### Replace")
+	suite.Contains(md, "| `availability_zone` **(forces replacement)** | `\"eu-west-1a\"` | `\"eu-west-1b\"` |")
+
+	_, err = p.Render("html")
+	suite.NotNil(err)
+}
+
+// TestPlanTestSuite : Test suite for plans
+func TestPlanTestSuite(t *testing.T) {
+	suite.Run(t, new(PlanTestSuite))
+}
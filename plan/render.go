@@ -0,0 +1,181 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/policy"
+)
+
+const (
+	// FORMATTEXT : plain text output, for terminals
+	FORMATTEXT = "text"
+	// FORMATMARKDOWN : markdown output, for pull requests and reviews
+	FORMATMARKDOWN = "markdown"
+)
+
+var symbols = map[string]string{
+	ACTIONCREATE:  "+",
+	ACTIONUPDATE:  "~",
+	ACTIONREPLACE: "-/+",
+	ACTIONDELETE:  "-",
+}
+
+// Render : renders the plan in the given format
+func (p *Plan) Render(format string) (string, error) {
+	switch format {
+	case FORMATTEXT, "":
+		return p.Text(), nil
+	case FORMATMARKDOWN:
+		return p.Markdown(), nil
+	}
+
+	return "", errors.New("unsupported plan format: " + format)
+}
+
+// Text : renders the plan as plain text
+func (p *Plan) Text() string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "Plan: %s\n", p.summary())
+
+	if p.Cost != nil {
+		fmt.Fprintf(&buf, "Cost: %s\n", p.Cost.String())
+	}
+
+	if p.Policy != nil && len(p.Policy.Violations) > 0 {
+		fmt.Fprintf(&buf, "Policy: %s\n", p.policySummary())
+
+		for _, v := range p.Policy.Violations {
+			fmt.Fprintf(&buf, "  [%s] %s: %s (%s)\n", v.Severity, v.ComponentID, v.Message, v.Rule)
+		}
+	}
+
+	for _, action := range ACTIONS {
+		changes := p.ByAction(action)
+		if len(changes) < 1 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n%s:\n", action)
+
+		for _, c := range changes {
+			fmt.Fprintf(&buf, "  %s %s\n", symbols[action], c.ComponentID)
+
+			for _, f := range c.Fields {
+				fmt.Fprintf(&buf, "      %s: %s => %s", f.Field, fieldValue(f, f.From), fieldValue(f, f.To))
+				if f.Immutable {
+					buf.WriteString(" (forces replacement)")
+				}
+				buf.WriteString("\n")
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// Markdown : renders the plan as markdown
+func (p *Plan) Markdown() string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "## Plan\n\n%s\n", p.summary())
+
+	if p.Cost != nil {
+		fmt.Fprintf(&buf, "\n**Estimated cost:** %s\n", p.Cost.String())
+	}
+
+	if p.Policy != nil && len(p.Policy.Violations) > 0 {
+		fmt.Fprintf(&buf, "\n**Policy:** %s\n\n", p.policySummary())
+
+		for _, v := range p.Policy.Violations {
+			fmt.Fprintf(&buf, "- **%s** `%s`: %s (`%s`)\n", v.Severity, v.ComponentID, v.Message, v.Rule)
+		}
+	}
+
+	for _, action := range ACTIONS {
+		changes := p.ByAction(action)
+		if len(changes) < 1 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n### %s%s\n\n", strings.ToUpper(action[:1]), action[1:])
+
+		for _, c := range changes {
+			fmt.Fprintf(&buf, "- `%s`\n", c.ComponentID)
+
+			if len(c.Fields) < 1 {
+				continue
+			}
+
+			buf.WriteString("\n  | Field | Before | After |\n  | --- | --- | --- |\n")
+
+			for _, f := range c.Fields {
+				field := "`" + f.Field + "`"
+				if f.Immutable {
+					field = field + " **(forces replacement)**"
+				}
+
+				fmt.Fprintf(&buf, "  | %s | `%s` | `%s` |\n", field, cell(fieldValue(f, f.From)), cell(fieldValue(f, f.To)))
+			}
+
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.String()
+}
+
+func (p *Plan) summary() string {
+	return fmt.Sprintf("%d to create, %d to update, %d to replace, %d to delete",
+		p.Summary[ACTIONCREATE],
+		p.Summary[ACTIONUPDATE],
+		p.Summary[ACTIONREPLACE],
+		p.Summary[ACTIONDELETE],
+	)
+}
+
+func (p *Plan) policySummary() string {
+	var denied, warned int
+
+	for _, v := range p.Policy.Violations {
+		if v.Severity == policy.DENY {
+			denied++
+		} else {
+			warned++
+		}
+	}
+
+	return fmt.Sprintf("%d denied, %d warnings", denied, warned)
+}
+
+// fieldValue : renders a field value, showing redacted values as they are
+func fieldValue(f FieldChange, v interface{}) string {
+	if f.Sensitive {
+		return fmt.Sprint(v)
+	}
+
+	return value(v)
+}
+
+// value : renders a field value as json, so pointers and collections are shown by value
+func value(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+// cell : escapes characters that would break a markdown table
+func cell(s string) string {
+	return strings.Replace(s, "|", "\\|", -1)
+}
@@ -0,0 +1,750 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression : a compiled policy expression. Expressions are evaluated against
+// a component, decoded from json, and support:
+//
+//	literals     "text", 'text', 22, 1.5, true, false, null, [1, 2]
+//	fields       acl, rules.ingress[0].ip
+//	variables    $provider, $project, $service
+//	comparisons  ==, !=, <, <=, >, >=, in
+//	logic        &&, ||, !, ( )
+//	functions    len(x), contains(list or text, x), matches(text, regex),
+//	             any(list, expr), all(list, expr), none(list, expr)
+//
+// Within any, all and none, fields are looked up on each item of the list
+// first, while the item itself is available as '_'. Fields that are not set
+// evaluate to null
+type Expression struct {
+	source string
+	root   node
+}
+
+// Compile : parses an expression
+func Compile(source string) (*Expression, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+
+	root, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", p.peek().value, p.peek().pos)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// String : returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval : evaluates the expression against a value, with the given variables
+func (e *Expression) Eval(value map[string]interface{}, vars map[string]interface{}) (interface{}, error) {
+	return e.root.eval(&scope{values: value, vars: vars})
+}
+
+// True : evaluates the expression, returning an error if it does not evaluate to a bool
+func (e *Expression) True(value map[string]interface{}, vars map[string]interface{}) (bool, error) {
+	v, err := e.Eval(value, vars)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression '%s' evaluated to %v, not a bool", e.source, v)
+	}
+
+	return b, nil
+}
+
+// scope : the values fields are looked up on. Scopes of list items fall back to their parent
+type scope struct {
+	values interface{}
+	vars   map[string]interface{}
+	parent *scope
+}
+
+func (s *scope) lookup(name string) interface{} {
+	for sc := s; sc != nil; sc = sc.parent {
+		if name == "_" && sc.parent != nil {
+			return sc.values
+		}
+
+		if m, ok := sc.values.(map[string]interface{}); ok {
+			if v, ok := m[name]; ok {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+// tokens
+
+const (
+	tokenIdent = iota
+	tokenVariable
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenPunct
+)
+
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+
+	r := []rune(s)
+
+	for i := 0; i < len(r); {
+		c := r[i]
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			var buf []rune
+			j := i + 1
+			for ; j < len(r) && r[j] != c; j++ {
+				if r[j] == '\\' && j+1 < len(r) {
+					j++
+				}
+				buf = append(buf, r[j])
+			}
+			if j >= len(r) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{tokenString, string(buf), i})
+			i = j + 1
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(r) && unicode.IsDigit(r[i+1])):
+			j := i + 1
+			for j < len(r) && (unicode.IsDigit(r[j]) || r[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, string(r[i:j]), i})
+			i = j
+		case c == '$' || c == '_' || unicode.IsLetter(c):
+			j := i + 1
+			for j < len(r) && (r[j] == '_' || unicode.IsLetter(r[j]) || unicode.IsDigit(r[j])) {
+				j++
+			}
+			kind := tokenIdent
+			if c == '$' {
+				kind = tokenVariable
+			}
+			tokens = append(tokens, token{kind, string(r[i:j]), i})
+			i = j
+		case strings.ContainsRune("()[],.", c):
+			tokens = append(tokens, token{tokenPunct, string(c), i})
+			i++
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(string(r[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected '%c' at position %d", c, i)
+			}
+			tokens = append(tokens, token{tokenOperator, op, i})
+			i += len(op)
+		}
+	}
+
+	return tokens, nil
+}
+
+// parser
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: -1, value: "end of expression"}
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *parser) is(values ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator && t.kind != tokenPunct && t.kind != tokenIdent {
+		return false
+	}
+
+	for _, v := range values {
+		if t.value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *parser) expect(value string) error {
+	if !p.is(value) {
+		t := p.peek()
+		return fmt.Errorf("expected '%s' but found '%s' at position %d", value, t.value, t.pos)
+	}
+
+	p.pos++
+
+	return nil
+}
+
+func (p *parser) expression() (node, error) {
+	return p.or()
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is("||") {
+		p.pos++
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{op: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is("&&") {
+		p.pos++
+
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{op: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) not() (node, error) {
+	if p.is("!") {
+		p.pos++
+
+		n, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		return negation{n}, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	left, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.is("==", "!=", "<", "<=", ">", ">=", "in") {
+		op := p.peek().value
+		p.pos++
+
+		right, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+
+		return comparison{op: op, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.peek()
+
+	switch {
+	case t.kind == tokenString:
+		p.pos++
+		return literal{t.value}, nil
+	case t.kind == tokenNumber:
+		p.pos++
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s' at position %d", t.value, t.pos)
+		}
+		return literal{f}, nil
+	case t.kind == tokenVariable:
+		p.pos++
+		return variable{t.value[1:]}, nil
+	case p.is("("):
+		p.pos++
+		n, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(")")
+	case p.is("["):
+		return p.list()
+	case t.kind == tokenIdent:
+		switch t.value {
+		case "true", "false":
+			p.pos++
+			return literal{t.value == "true"}, nil
+		case "null":
+			p.pos++
+			return literal{nil}, nil
+		}
+
+		if p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].value == "(" {
+			return p.call()
+		}
+
+		return p.path()
+	}
+
+	return nil, fmt.Errorf("unexpected '%s' at position %d", t.value, t.pos)
+}
+
+func (p *parser) list() (node, error) {
+	var items []node
+
+	p.pos++
+
+	for !p.is("]") {
+		n, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, n)
+
+		if !p.is(",") {
+			break
+		}
+		p.pos++
+	}
+
+	return list{items}, p.expect("]")
+}
+
+func (p *parser) path() (node, error) {
+	n := path{parts: []interface{}{p.peek().value}}
+	p.pos++
+
+	for {
+		switch {
+		case p.is("."):
+			p.pos++
+			t := p.peek()
+			if t.kind != tokenIdent {
+				return nil, fmt.Errorf("expected a field name but found '%s' at position %d", t.value, t.pos)
+			}
+			n.parts = append(n.parts, t.value)
+			p.pos++
+		case p.is("["):
+			p.pos++
+			t := p.peek()
+			i, err := strconv.Atoi(t.value)
+			if t.kind != tokenNumber || err != nil {
+				return nil, fmt.Errorf("expected an index but found '%s' at position %d", t.value, t.pos)
+			}
+			n.parts = append(n.parts, i)
+			p.pos++
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) call() (node, error) {
+	name := p.peek()
+	p.pos += 2
+
+	var args []node
+
+	for !p.is(")") {
+		n, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, n)
+
+		if !p.is(",") {
+			break
+		}
+		p.pos++
+	}
+
+	err := p.expect(")")
+	if err != nil {
+		return nil, err
+	}
+
+	arity, ok := functions[name.value]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s' at position %d", name.value, name.pos)
+	}
+
+	if len(args) != arity {
+		return nil, fmt.Errorf("function '%s' takes %d arguments, not %d", name.value, arity, len(args))
+	}
+
+	return call{name: name.value, args: args}, nil
+}
+
+// nodes
+
+type node interface {
+	eval(s *scope) (interface{}, error)
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (n literal) eval(s *scope) (interface{}, error) {
+	return n.value, nil
+}
+
+type variable struct {
+	name string
+}
+
+func (n variable) eval(s *scope) (interface{}, error) {
+	return s.vars[n.name], nil
+}
+
+type list struct {
+	items []node
+}
+
+func (n list) eval(s *scope) (interface{}, error) {
+	values := make([]interface{}, len(n.items))
+
+	for i, item := range n.items {
+		v, err := item.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+type path struct {
+	parts []interface{}
+}
+
+func (n path) eval(s *scope) (interface{}, error) {
+	v := s.lookup(n.parts[0].(string))
+
+	for _, part := range n.parts[1:] {
+		switch x := part.(type) {
+		case string:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, nil
+			}
+			v = m[x]
+		case int:
+			l, ok := v.([]interface{})
+			if !ok || x < 0 || x >= len(l) {
+				return nil, nil
+			}
+			v = l[x]
+		}
+	}
+
+	return v, nil
+}
+
+type negation struct {
+	n node
+}
+
+func (n negation) eval(s *scope) (interface{}, error) {
+	v, err := boolean(n.n, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return !v, nil
+}
+
+type logical struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n logical) eval(s *scope) (interface{}, error) {
+	l, err := boolean(n.left, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "&&" && !l || n.op == "||" && l {
+		return l, nil
+	}
+
+	return boolean(n.right, s)
+}
+
+type comparison struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n comparison) eval(s *scope) (interface{}, error) {
+	l, err := n.left.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := n.right.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "in":
+		items, ok := r.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		for _, item := range items {
+			if equal(l, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return order(n.op, l, r), nil
+}
+
+type call struct {
+	name string
+	args []node
+}
+
+// functions : the supported functions, along with the number of arguments they take
+var functions = map[string]int{
+	"len":      1,
+	"contains": 2,
+	"matches":  2,
+	"any":      2,
+	"all":      2,
+	"none":     2,
+}
+
+func (n call) eval(s *scope) (interface{}, error) {
+	v, err := n.args[0].eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.name {
+	case "len":
+		switch x := v.(type) {
+		case string:
+			return float64(len(x)), nil
+		case []interface{}:
+			return float64(len(x)), nil
+		case map[string]interface{}:
+			return float64(len(x)), nil
+		}
+		return float64(0), nil
+	case "contains":
+		a, err := n.args[1].eval(s)
+		if err != nil {
+			return nil, err
+		}
+		switch x := v.(type) {
+		case string:
+			as, ok := a.(string)
+			return ok && strings.Contains(x, as), nil
+		case []interface{}:
+			for _, item := range x {
+				if equal(item, a) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	case "matches":
+		a, err := n.args[1].eval(s)
+		if err != nil {
+			return nil, err
+		}
+		pattern, ok := a.(string)
+		if !ok {
+			return nil, errors.New("the pattern given to matches must be text")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		text, ok := v.(string)
+		return ok && re.MatchString(text), nil
+	}
+
+	// any, all and none evaluate their second argument on each item of the list
+	items, _ := v.([]interface{})
+
+	for _, item := range items {
+		matched, err := boolean(n.args[1], &scope{values: item, vars: s.vars, parent: s})
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case n.name == "any" && matched:
+			return true, nil
+		case n.name == "all" && !matched:
+			return false, nil
+		case n.name == "none" && matched:
+			return false, nil
+		}
+	}
+
+	return n.name != "any", nil
+}
+
+func boolean(n node, s *scope) (bool, error) {
+	v, err := n.eval(s)
+	if err != nil {
+		return false, err
+	}
+
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case nil:
+		return false, nil
+	}
+
+	return false, fmt.Errorf("expected a bool, but found %v", v)
+}
+
+func equal(a, b interface{}) bool {
+	af, aok := number(a)
+	bf, bok := number(b)
+
+	if aok && bok {
+		return af == bf
+	}
+
+	switch x := a.(type) {
+	case string, bool, nil:
+		return x == b
+	}
+
+	return false
+}
+
+func order(op string, a, b interface{}) bool {
+	var c int
+
+	af, aok := number(a)
+	bf, bok := number(b)
+	as, asok := a.(string)
+	bs, bsok := b.(string)
+
+	switch {
+	case aok && bok:
+		switch {
+		case af < bf:
+			c = -1
+		case af > bf:
+			c = 1
+		}
+	case asok && bsok:
+		c = strings.Compare(as, bs)
+	default:
+		return false
+	}
+
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+
+	return c >= 0
+}
+
+func number(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	}
+
+	return 0, false
+}
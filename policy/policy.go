@@ -0,0 +1,281 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/r3labs/graph"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// DENY : components violating the rule prevent the mapping from being returned
+	DENY = "deny"
+	// WARN : violations are returned along with the mapping
+	WARN = "warn"
+)
+
+// POLICIESFILE : the environment variable setting the rules applied to every request
+const POLICIESFILE = "POLICIES_FILE"
+
+// Rule : a check made on every component of a type. Components the rule
+// applies to, as set by 'when', must satisfy 'assert'
+type Rule struct {
+	Name      string `json:"name" yaml:"name"`
+	Severity  string `json:"severity" yaml:"severity"`
+	Message   string `json:"message,omitempty" yaml:"message,omitempty"`
+	Provider  string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Component string `json:"component" yaml:"component"`
+	When      string `json:"when,omitempty" yaml:"when,omitempty"`
+	Assert    string `json:"assert" yaml:"assert"`
+}
+
+// Context : the service a graph is evaluated for, available to rules as
+// the $provider, $project and $service variables
+type Context struct {
+	Provider string
+	Project  string
+	Service  string
+}
+
+// Violation : a component that does not satisfy a rule
+type Violation struct {
+	Rule          string `json:"rule"`
+	Severity      string `json:"severity"`
+	ComponentID   string `json:"component_id"`
+	ComponentType string `json:"component_type"`
+	Message       string `json:"message"`
+}
+
+// Result : the outcome of evaluating rules against a graph. A graph passes
+// unless a rule with a deny severity is violated
+type Result struct {
+	Passed     bool        `json:"passed"`
+	Violations []Violation `json:"violations"`
+}
+
+// RuleError : a rule that could not be compiled or evaluated
+type RuleError struct {
+	Rule        string
+	ComponentID string
+	Err         error
+}
+
+// Error : returns the error as a string
+func (e *RuleError) Error() string {
+	if e.ComponentID != "" {
+		return "policy '" + e.Rule + "' could not be evaluated on " + e.ComponentID + ": " + e.Err.Error()
+	}
+
+	return "policy '" + e.Rule + "' is invalid: " + e.Err.Error()
+}
+
+type compiled struct {
+	rule   Rule
+	when   *Expression
+	assert *Expression
+}
+
+// Load : loads rules from a yaml or json file, holding a list of rules
+func Load(path string) ([]Rule, error) {
+	var rules []Rule
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(data, &rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// defaultRules : the rules evaluated on every request, along with its own
+var defaultRules []Rule
+
+// LoadDefaultRules : loads the rules set by POLICIES_FILE once, to be evaluated
+// on every request. Returns an error if the file can not be loaded or one of
+// its rules is invalid
+func LoadDefaultRules() error {
+	path := os.Getenv(POLICIESFILE)
+	if path == "" {
+		return nil
+	}
+
+	rules, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	err = Validate(rules)
+	if err != nil {
+		return err
+	}
+
+	defaultRules = rules
+
+	return nil
+}
+
+// DefaultRules : returns the rules set by POLICIES_FILE, if any
+func DefaultRules() []Rule {
+	return defaultRules
+}
+
+// Validate : compiles every rule, returning an error for the first that is invalid
+func Validate(rules []Rule) error {
+	_, err := compile(rules)
+	return err
+}
+
+// Evaluate : evaluates rules against every component of a graph
+func Evaluate(g *graph.Graph, rules []Rule, ctx Context) (*Result, error) {
+	r := Result{
+		Passed:     true,
+		Violations: []Violation{},
+	}
+
+	crules, err := compile(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]interface{}{
+		"provider": ctx.Provider,
+		"project":  ctx.Project,
+		"service":  ctx.Service,
+	}
+
+	for _, c := range g.Components {
+		var value map[string]interface{}
+
+		for _, cr := range crules {
+			if !cr.applies(c, ctx) {
+				continue
+			}
+
+			// components are only decoded once a rule applies to them
+			if value == nil {
+				value, err = decode(c)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			ok, err := cr.check(value, vars)
+			if err != nil {
+				return nil, &RuleError{Rule: cr.rule.Name, ComponentID: c.GetID(), Err: err}
+			}
+
+			if ok {
+				continue
+			}
+
+			r.Violations = append(r.Violations, cr.violation(c))
+
+			if cr.rule.Severity == DENY {
+				r.Passed = false
+			}
+		}
+	}
+
+	return &r, nil
+}
+
+func compile(rules []Rule) ([]compiled, error) {
+	var err error
+
+	crules := make([]compiled, len(rules))
+
+	for i, rule := range rules {
+		crules[i].rule = rule
+
+		if rule.Name == "" {
+			return nil, &RuleError{Rule: "#" + strconv.Itoa(i+1), Err: errors.New("rules must be named")}
+		}
+
+		if rule.Severity != DENY && rule.Severity != WARN {
+			return nil, &RuleError{Rule: rule.Name, Err: errors.New("severity must be either '" + DENY + "' or '" + WARN + "'")}
+		}
+
+		if rule.Assert == "" {
+			return nil, &RuleError{Rule: rule.Name, Err: errors.New("rules must set an assertion")}
+		}
+
+		crules[i].assert, err = Compile(rule.Assert)
+		if err != nil {
+			return nil, &RuleError{Rule: rule.Name, Err: err}
+		}
+
+		if rule.When != "" {
+			crules[i].when, err = Compile(rule.When)
+			if err != nil {
+				return nil, &RuleError{Rule: rule.Name, Err: err}
+			}
+		}
+	}
+
+	return crules, nil
+}
+
+func (cr compiled) applies(c graph.Component, ctx Context) bool {
+	if c.GetType() == "credentials" {
+		return false
+	}
+
+	if cr.rule.Provider != "" && cr.rule.Provider != ctx.Provider {
+		return false
+	}
+
+	return cr.rule.Component == "" || cr.rule.Component == "*" || cr.rule.Component == c.GetType()
+}
+
+func (cr compiled) check(value map[string]interface{}, vars map[string]interface{}) (bool, error) {
+	if cr.when != nil {
+		applies, err := cr.when.True(value, vars)
+		if err != nil || !applies {
+			return true, err
+		}
+	}
+
+	return cr.assert.True(value, vars)
+}
+
+func (cr compiled) violation(c graph.Component) Violation {
+	msg := cr.rule.Message
+	if msg == "" {
+		msg = "does not satisfy '" + cr.assert.String() + "'"
+	}
+
+	return Violation{
+		Rule:          cr.rule.Name,
+		Severity:      cr.rule.Severity,
+		ComponentID:   c.GetID(),
+		ComponentType: c.GetType(),
+		Message:       msg,
+	}
+}
+
+// decode : converts a component to the generic values rules are evaluated on
+func decode(c graph.Component) (map[string]interface{}, error) {
+	var value map[string]interface{}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &value)
+
+	return value, err
+}
@@ -0,0 +1,158 @@
+package policy_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/ernestio/definition-mapper/policy"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// PolicyTestSuite : Test suite for policy rules
+type PolicyTestSuite struct {
+	suite.Suite
+	Graph *graph.Graph
+	Rules []policy.Rule
+}
+
+// SetupTest : Setup test suite
+func (suite *PolicyTestSuite) SetupTest() {
+	sg := &components.SecurityGroup{Name: "web"}
+	sg.Rules.Ingress = []components.SecurityGroupRule{
+		{IP: "10.0.0.0/16", From: 22, To: 22, Protocol: "tcp"},
+		{IP: "0.0.0.0/0", From: 0, To: 65535, Protocol: "tcp"},
+	}
+
+	suite.Graph = graph.New()
+	for _, c := range []graph.Component{sg, &components.S3Bucket{Name: "assets", ACL: "public-read"}, &components.RDSInstance{Name: "db"}} {
+		c.SetDefaultVariables()
+		suite.Graph.AddComponent(c)
+	}
+
+	suite.Rules = []policy.Rule{
+		{
+			Name:      "no-public-ssh",
+			Severity:  policy.DENY,
+			Message:   "ssh must not be open to the internet",
+			Component: "firewall",
+			Assert:    `none(rules.ingress, ip == "0.0.0.0/0" && from_port <= 22 && to_port >= 22)`,
+		},
+		{
+			Name:      "private-buckets",
+			Severity:  policy.WARN,
+			Component: "s3",
+			Assert:    `acl == "private"`,
+		},
+		{
+			Name:      "prod-multi-az",
+			Severity:  policy.DENY,
+			Component: "rds_instance",
+			When:      `$service == "prod"`,
+			Assert:    `multi_az == true`,
+		},
+	}
+}
+
+// TestEvaluate : Testing the evaluation of rules against a graph
+func (suite *PolicyTestSuite) TestEvaluate() {
+	r, err := policy.Evaluate(suite.Graph, suite.Rules, policy.Context{Provider: "aws", Project: "acme", Service: "dev"})
+	suite.Nil(err)
+	suite.False(r.Passed)
+	suite.Len(r.Violations, 2)
+
+	violations := map[string]policy.Violation{}
+	for _, v := range r.Violations {
+		violations[v.Rule] = v
+	}
+
+	suite.Equal("firewall::web", violations["no-public-ssh"].ComponentID)
+	suite.Equal("ssh must not be open to the internet", violations["no-public-ssh"].Message)
+	suite.Equal(policy.WARN, violations["private-buckets"].Severity)
+	suite.Equal(`does not satisfy 'acl == "private"'`, violations["private-buckets"].Message)
+
+	r, err = policy.Evaluate(suite.Graph, suite.Rules[1:], policy.Context{Provider: "aws", Project: "acme", Service: "prod"})
+	suite.Nil(err)
+	suite.False(r.Passed)
+	suite.Len(r.Violations, 2)
+
+	r, err = policy.Evaluate(suite.Graph, suite.Rules[1:2], policy.Context{Provider: "aws", Service: "prod"})
+	suite.Nil(err)
+	suite.True(r.Passed)
+	suite.Len(r.Violations, 1)
+}
+
+// TestValidate : Testing invalid rules are rejected
+func (suite *PolicyTestSuite) TestValidate() {
+	suite.Nil(policy.Validate(suite.Rules))
+
+	err := policy.Validate([]policy.Rule{{Name: "bad", Severity: policy.DENY, Assert: "acl == "}})
+	suite.NotNil(err)
+
+	err = policy.Validate([]policy.Rule{{Name: "bad", Severity: "block", Assert: "true"}})
+	suite.EqualError(err, "policy 'bad' is invalid: severity must be either 'deny' or 'warn'")
+
+	err = policy.Validate([]policy.Rule{{Name: "bad", Severity: policy.WARN, Assert: "unknown(acl)"}})
+	suite.NotNil(err)
+}
+
+// TestLoadDefaultRules : Testing invalid rules set by POLICIES_FILE are rejected
+func (suite *PolicyTestSuite) TestLoadDefaultRules() {
+	f, err := ioutil.TempFile("", "policies")
+	suite.Nil(err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString("- name: private-buckets\n  severity: warn\n  component: s3\n  assert: acl == \"private\"\n")
+	suite.Nil(err)
+	suite.Nil(f.Close())
+
+	os.Setenv(policy.POLICIESFILE, f.Name())
+	defer os.Unsetenv(policy.POLICIESFILE)
+
+	suite.Nil(policy.LoadDefaultRules())
+	suite.Len(policy.DefaultRules(), 1)
+	suite.Equal("private-buckets", policy.DefaultRules()[0].Name)
+
+	suite.Nil(ioutil.WriteFile(f.Name(), []byte("- name: bad\n  severity: block\n  assert: \"true\"\n"), 0644))
+	suite.NotNil(policy.LoadDefaultRules())
+
+	os.Setenv(policy.POLICIESFILE, f.Name()+".missing")
+	suite.NotNil(policy.LoadDefaultRules())
+}
+
+// TestExpressions : Testing the evaluation of expressions
+func (suite *PolicyTestSuite) TestExpressions() {
+	value := map[string]interface{}{
+		"name": "web-1",
+		"size": 20.0,
+		"tags": map[string]interface{}{"env": "prod"},
+		"ips":  []interface{}{"10.0.0.1", "10.0.0.2"},
+	}
+
+	tests := map[string]bool{
+		`size > 10 && size <= 20`:               true,
+		`tags.env in ["prod", "staging"]`:       true,
+		`len(ips) == 2 && ips[1] == "10.0.0.2"`: true,
+		`matches(name, "^web-[0-9]+$")`:         true,
+		`contains(ips, "10.0.0.3")`:             false,
+		`all(ips, matches(_, "^10\\."))`:        true,
+		`missing == null`:                       true,
+		`!(name == $service)`:                   false,
+	}
+
+	for source, expected := range tests {
+		e, err := policy.Compile(source)
+		suite.Nil(err, source)
+
+		ok, err := e.True(value, map[string]interface{}{"service": "web-1"})
+		suite.Nil(err, source)
+		suite.Equal(expected, ok, source)
+	}
+}
+
+// TestPolicyTestSuite : Test suite for policy rules
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
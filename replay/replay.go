@@ -0,0 +1,235 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ernestio/definition-mapper/handlers"
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/request"
+	"github.com/r3labs/graph"
+)
+
+// ErrNoGolden : returned when a case has no golden file to compare against
+var ErrNoGolden = errors.New("no golden file recorded")
+
+// Case : a captured mapping request, along with the subject it was sent on.
+// Captures are stored one case per line, i.e.
+// {"name": "aws-create", "subject": "mapping.get.create", "request": {...}}
+type Case struct {
+	Name    string          `json:"name,omitempty"`
+	Subject string          `json:"subject"`
+	Request json.RawMessage `json:"request"`
+}
+
+// Mismatch : the first line where a case's output differs from its golden file
+type Mismatch struct {
+	Line int
+	Want string
+	Got  string
+}
+
+// Error : returns the mismatch as a string
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("output differs from golden file at line %d:\n  want: %s\n  got:  %s", m.Line, m.Want, m.Got)
+}
+
+// Load : reads a capture file. Cases without a name are named after their
+// line, operation and provider, i.e. '003-create-aws'
+func Load(path string) ([]Case, error) {
+	var cases []Case
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; s.Scan(); line++ {
+		var c Case
+
+		data := bytes.TrimSpace(s.Bytes())
+		if len(data) < 1 {
+			continue
+		}
+
+		err = json.Unmarshal(data, &c)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, line, err.Error())
+		}
+
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("%03d-%s-%s", line, c.Operation(), c.provider())
+		}
+
+		cases = append(cases, c)
+	}
+
+	return cases, s.Err()
+}
+
+// Operation : returns the mapping operation of the case's subject
+func (c Case) Operation() string {
+	parts := strings.Split(c.Subject, ".")
+	return parts[len(parts)-1]
+}
+
+// GoldenFile : returns the path of the case's golden file within a directory
+func (c Case) GoldenFile(dir string) string {
+	return filepath.Join(dir, c.Name+".json")
+}
+
+func (c Case) provider() string {
+	r, err := c.request()
+	if err != nil || r.Provider() == "" {
+		return "unknown"
+	}
+
+	return r.Provider()
+}
+
+func (c Case) request() (*request.Request, error) {
+	var r request.Request
+
+	err := json.Unmarshal(c.Request, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// Run : maps the case's request with the handler of its operation, returning
+// the graph as it would be returned to the requester. Failed requests return
+// their errors, so changes to validation are caught as well
+func Run(c Case) ([]byte, error) {
+	var h func(*request.Request) (*graph.Graph, error)
+
+	encode := libmapper.RedactChangelogs
+
+	switch c.Operation() {
+	case "create":
+		h = handlers.Create
+	case "update":
+		h = handlers.Update
+	case "delete":
+		h = handlers.Delete
+	case "import":
+		h = handlers.Import
+	case "diff":
+		h = handlers.Diff
+		encode = libmapper.RedactGraph
+	default:
+		return nil, errors.New("unsupported replay operation: " + c.Operation())
+	}
+
+	r, err := c.request()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+
+	g, err := h(r)
+	if err == nil {
+		data, err = encode(g)
+	}
+
+	if err != nil {
+		data, err = json.Marshal(map[string]libmapper.Errors{"_errors": libmapper.ToErrors(err, libmapper.ErrCodeInternal)})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return indent(data)
+}
+
+// Replay : runs a case and compares its output against its golden file in the
+// given directory. If update is set, the golden file is written instead
+func Replay(c Case, dir string, update bool) error {
+	got, err := Run(c)
+	if err != nil {
+		return err
+	}
+
+	path := c.GoldenFile(dir)
+
+	if update {
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return err
+		}
+
+		return ioutil.WriteFile(path, got, 0644)
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ErrNoGolden
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return compare(want, got)
+}
+
+// indent : formats json consistently, so golden files can be reviewed as text
+func indent(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := json.Indent(&buf, data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// compare : returns the first line that differs between the golden and actual output
+func compare(want, got []byte) error {
+	if bytes.Equal(want, got) {
+		return nil
+	}
+
+	wl := strings.Split(string(want), "\n")
+	gl := strings.Split(string(got), "\n")
+
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		w := line(wl, i)
+		g := line(gl, i)
+
+		if w != g {
+			return &Mismatch{Line: i + 1, Want: w, Got: g}
+		}
+	}
+
+	return nil
+}
+
+func line(lines []string, i int) string {
+	if i >= len(lines) {
+		return "<end of file>"
+	}
+
+	return lines[i]
+}
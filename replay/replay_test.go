@@ -0,0 +1,85 @@
+package replay_test
+
+// Basic imports
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ernestio/definition-mapper/replay"
+	"github.com/stretchr/testify/suite"
+)
+
+var update = flag.Bool("update", false, "record the golden files of all captured requests")
+
+// ReplayTestSuite : Test suite for replay
+type ReplayTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+// SetupTest : creates a directory for golden files
+func (suite *ReplayTestSuite) SetupTest() {
+	var err error
+
+	suite.dir, err = ioutil.TempDir("", "replay")
+	suite.Nil(err)
+}
+
+// TearDownTest : removes the golden files
+func (suite *ReplayTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+// TestLoad : Testing cases are loaded from a capture file and named
+func (suite *ReplayTestSuite) TestLoad() {
+	path := filepath.Join(suite.dir, "requests.jsonl")
+	err := ioutil.WriteFile(path, []byte(`{"name": "named", "subject": "mapping.get.create", "request": {}}
+
+{"subject": "mapping.get.diff", "request": {"credentials": {"type": "aws"}}}
+`), 0644)
+	suite.Nil(err)
+
+	cases, err := replay.Load(path)
+	suite.Nil(err)
+	suite.Len(cases, 2)
+	suite.Equal("named", cases[0].Name)
+	suite.Equal("003-diff-aws", cases[1].Name)
+	suite.Equal("diff", cases[1].Operation())
+}
+
+// TestReplay : Testing output is compared against recorded golden files
+func (suite *ReplayTestSuite) TestReplay() {
+	cases, err := replay.Load("testdata/requests.jsonl")
+	suite.Nil(err)
+
+	var c replay.Case
+	for _, tc := range cases {
+		if tc.Name == "unsupported-provider" {
+			c = tc
+		}
+	}
+
+	suite.Equal(replay.ErrNoGolden, replay.Replay(c, suite.dir, false))
+	suite.Nil(replay.Replay(c, suite.dir, true))
+	suite.Nil(replay.Replay(c, suite.dir, false))
+
+	err = ioutil.WriteFile(c.GoldenFile(suite.dir), []byte("{}\n"), 0644)
+	suite.Nil(err)
+
+	err = replay.Replay(c, suite.dir, false)
+	suite.IsType(&replay.Mismatch{}, err)
+	suite.Equal(1, err.(*replay.Mismatch).Line)
+}
+
+// TestReplayTestSuite : Test suite for replay
+func TestReplayTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplayTestSuite))
+}
+
+// TestGolden : Testing captured requests still map to their recorded output
+func TestGolden(t *testing.T) {
+	replay.Test(t, "testdata/requests.jsonl", "testdata/golden", *update)
+}
@@ -0,0 +1,34 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package replay
+
+import (
+	"testing"
+)
+
+// Test : replays every case of a capture file as a subtest, failing those whose
+// output differs from their golden file, or that have no golden file recorded.
+// Golden files are recorded by running the tests with update set
+func Test(t *testing.T, capture, dir string, update bool) {
+	cases, err := Load(capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.Name, func(t *testing.T) {
+			err := Replay(c, dir, update)
+
+			switch {
+			case err == ErrNoGolden:
+				t.Error("no golden file at " + c.GoldenFile(dir) + ", record it with -update")
+			case err != nil:
+				t.Error(err)
+			}
+		})
+	}
+}
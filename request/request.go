@@ -5,33 +5,82 @@
 package request
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"strings"
 
+	"github.com/ernestio/definition-mapper/cost"
 	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/libmapper/providers"
+	"github.com/ernestio/definition-mapper/logger"
+	"github.com/ernestio/definition-mapper/policy"
 	"github.com/r3labs/graph"
 )
 
 // Request :
 type Request struct {
-	ID          string                 `json:"id,omitempty"`
-	Name        string                 `json:"name,omitempty"`
-	UserID      int                    `json:"user_id"`
-	Username    string                 `json:"username"`
-	Changelog   bool                   `json:"changelog"`
-	Filters     []string               `json:"filters,omitempty"`
-	Definition  map[string]interface{} `json:"definition,omitempty"`
-	From        map[string]interface{} `json:"from,omitempty"`
-	To          map[string]interface{} `json:"to,omitempty"`
-	Credentials map[string]interface{} `json:"credentials,omitempty"`
+	ID            string                   `json:"id,omitempty"`
+	CorrelationID string                   `json:"correlation_id,omitempty"`
+	Name          string                   `json:"name,omitempty"`
+	UserID        int                      `json:"user_id"`
+	Username      string                   `json:"username"`
+	Changelog     bool                     `json:"changelog"`
+	Strict        bool                     `json:"strict,omitempty"`
+	Format        string                   `json:"format,omitempty"`
+	Filters       []string                 `json:"filters,omitempty"`
+	Definition    map[string]interface{}   `json:"definition,omitempty"`
+	Merge         string                   `json:"merge,omitempty"`
+	Variables     map[string]interface{}   `json:"variables,omitempty"`
+	Modules       map[string]interface{}   `json:"modules,omitempty"`
+	From          map[string]interface{}   `json:"from,omitempty"`
+	To            map[string]interface{}   `json:"to,omitempty"`
+	Credentials   map[string]interface{}   `json:"credentials,omitempty"`
+	Policies      []policy.Rule            `json:"policies,omitempty"`
+	PolicyResult  *policy.Result           `json:"-"`
+	Warnings      libmapper.Errors         `json:"-"`
+	Secrets       libmapper.SecretResolver `json:"-"`
+	Prices        *cost.Prices             `json:"-"`
+	Log           *logger.Logger           `json:"-"`
+	Context       context.Context          `json:"-"`
 }
 
 // DefinitionToGraph : converts a Defintiion to a graph
 func (r *Request) DefinitionToGraph(m libmapper.Mapper) (*graph.Graph, error) {
-	d, err := m.LoadDefinition(r.Definition)
+	gd, err := r.ResolveDefinition()
 	if err != nil {
 		return nil, err
 	}
 
+	if err = r.Err(); err != nil {
+		return nil, err
+	}
+
+	// unknown keys are only treated as errors in strict mode, otherwise they
+	// are returned along with the mapping as warnings
+	unknown := libmapper.UnknownDefinitionKeys(m, gd)
+	if r.Strict && len(unknown) > 0 {
+		return nil, unknown
+	}
+
+	r.Warnings = libmapper.AsWarnings(unknown)
+
+	refs := libmapper.SecretReferences(gd)
+
+	gd, err = r.ResolveSecrets(gd)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := m.LoadDefinition(gd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.Err(); err != nil {
+		return nil, err
+	}
+
 	g, err := m.ConvertDefinition(d)
 	if err != nil {
 		return nil, err
@@ -43,7 +92,12 @@ func (r *Request) DefinitionToGraph(m libmapper.Mapper) (*graph.Graph, error) {
 	g.UserID = r.UserID
 	g.Username = r.Username
 
-	err = g.AddComponent(m.ProviderCredentials(r.Credentials))
+	// secret references are kept on the mapping, so they can be returned in
+	// place of their values on definitions converted from it
+	creds := m.ProviderCredentials(r.Credentials)
+	libmapper.SetSecretReferences(creds, refs)
+
+	err = g.AddComponent(creds)
 	if err != nil {
 		return nil, err
 	}
@@ -51,16 +105,124 @@ func (r *Request) DefinitionToGraph(m libmapper.Mapper) (*graph.Graph, error) {
 	return g, nil
 }
 
+// ResolveDefinition : returns the definition with all variables resolved,
+// using the request's variables as overrides, and all modules expanded
+// from the request's module sources
+func (r *Request) ResolveDefinition() (map[string]interface{}, error) {
+	d, errs := libmapper.ResolveDefinition(r.Definition, r.Variables, r.Modules)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return d, nil
+}
+
+// ResolveSecrets : returns the definition with all secret references resolved,
+// using the request's secret resolver or the default one if it is not set
+func (r *Request) ResolveSecrets(d map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+
+	sr := r.Secrets
+	if sr == nil {
+		sr, err = libmapper.DefaultSecretResolver()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rd, errs := libmapper.ResolveSecrets(d, sr)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return rd, nil
+}
+
+// Logger : returns the logger used while handling the request. Unless one has
+// been set, lines are written to the default logger with the request's fields
+func (r *Request) Logger() *logger.Logger {
+	if r.Log != nil {
+		return r.Log
+	}
+
+	fields := logger.Fields{}
+
+	for k, v := range map[string]string{"id": r.ID, "name": r.Name, "username": r.Username, "correlation_id": r.CorrelationID} {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+
+	// credentials can be echoed back on the text of errors from a provider
+	var secrets []string
+
+	sensitive := libmapper.SensitiveFields()
+	for k, v := range r.Credentials {
+		if s, ok := v.(string); ok && sensitive[k] {
+			secrets = append(secrets, s)
+		}
+	}
+
+	r.Log = logger.Default.WithFields(fields).WithSecrets(secrets...)
+
+	return r.Log
+}
+
+// Mapper : returns the mapper for the request's provider, writing its log lines with the request's logger
+func (r *Request) Mapper() (libmapper.Mapper, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	m, err := providers.NewMapper(r.Provider())
+	if err != nil {
+		return nil, err
+	}
+
+	if lm, ok := m.(libmapper.LoggingMapper); ok {
+		lm.SetLogger(r.Logger())
+	}
+
+	return m, nil
+}
+
+// PriceTable : returns the request's price table, or the default one if it is not set
+func (r *Request) PriceTable() *cost.Prices {
+	if r.Prices != nil {
+		return r.Prices
+	}
+
+	return cost.DefaultPrices()
+}
+
 // ToMapping : loads the "to" graph mapping as a graph
 func (r *Request) ToMapping(m libmapper.Mapper) (*graph.Graph, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
 	return m.LoadGraph(r.To)
 }
 
 // FromMapping : loads the "from" graph mapping as a graph
 func (r *Request) FromMapping(m libmapper.Mapper) (*graph.Graph, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
 	return m.LoadGraph(r.From)
 }
 
+// Err : returns a timeout error once the request's context is done, so
+// handlers stop working on requests the requester is no longer waiting for
+func (r *Request) Err() error {
+	if r.Context == nil || r.Context.Err() == nil {
+		return nil
+	}
+
+	return libmapper.NewError(libmapper.ErrCodeTimeout, "request cancelled: "+r.Context.Err().Error())
+}
+
 // Provider : returns the provider/env type
 func (r *Request) Provider() string {
 	p, _ := r.Credentials["type"].(string)
@@ -68,15 +230,29 @@ func (r *Request) Provider() string {
 }
 
 // ImportFilters : returns the collection of import filters used on an import
-func (r *Request) ImportFilters() []string {
-	switch r.Provider() {
-	case "azure", "azure-fake":
-		return r.Filters
-	default:
-		return []string{env(r.Name)}
+// by a provider's mapper. Imports are filtered by the service name, unless
+// the mapper chooses its own filters
+func (r *Request) ImportFilters(m libmapper.Mapper) []string {
+	if s, ok := m.(libmapper.ImportFilterSelector); ok {
+		return s.ImportFilters(r.Filters, env(r.Name))
 	}
+
+	return []string{env(r.Name)}
 }
 
 func env(e string) string {
 	return strings.Split(e, "/")[1]
 }
+
+// NewCorrelationID : returns a random id used to tie together the log lines of
+// a request, when the requester has not set one
+func NewCorrelationID() string {
+	id := make([]byte, 16)
+
+	_, err := rand.Read(id)
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
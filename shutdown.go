@@ -0,0 +1,145 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ernestio/definition-mapper/logger"
+	"github.com/nats-io/go-nats"
+)
+
+const (
+	// DEFAULTGRACEPERIOD : the time in-flight requests are given to complete on shutdown
+	DEFAULTGRACEPERIOD = time.Second * 30
+	// DRAININTERVAL : how often subscriptions are checked while they are drained
+	DRAININTERVAL = time.Millisecond * 100
+)
+
+var (
+	gracePeriod   = DEFAULTGRACEPERIOD
+	subscriptions []*nats.Subscription
+	servers       []*http.Server
+	inflight      sync.WaitGroup
+	draining      int32
+)
+
+// configureShutdown : sets the shutdown grace period from SHUTDOWN_GRACE_PERIOD
+func configureShutdown() {
+	if g := os.Getenv("SHUTDOWN_GRACE_PERIOD"); g != "" {
+		d, err := time.ParseDuration(g)
+		if err != nil {
+			logger.Warn("invalid SHUTDOWN_GRACE_PERIOD value, using the default", logger.Fields{"value": g})
+		} else {
+			gracePeriod = d
+		}
+	}
+}
+
+// subscribe : subscribes a handler to a subject on the queue group, keeping
+// track of the subscription so it can be drained on shutdown
+func subscribe(subject string, h messageHandler) {
+	sub, err := n.QueueSubscribe(subject, queue, serve(h))
+	if err != nil {
+		logger.Error("could not subscribe", logger.Fields{"subject": subject, "error": err})
+		return
+	}
+
+	// messages beyond the pending limit are dropped by the client, leaving the
+	// requester to time out rather than queueing without bound
+	err = sub.SetPendingLimits(pending, nats.DefaultSubPendingBytesLimit)
+	if err != nil {
+		logger.Warn("could not set the pending limit", logger.Fields{"subject": subject, "error": err})
+	}
+
+	subscriptions = append(subscriptions, sub)
+}
+
+// listen : starts an http server, keeping track of it so it can be stopped on shutdown
+func listen(addr string, h http.Handler) {
+	s := &http.Server{Addr: addr, Handler: h}
+	servers = append(servers, s)
+
+	go func() {
+		err := s.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error("http server stopped", logger.Fields{"addr": addr, "error": err})
+		}
+	}()
+}
+
+// isDraining : returns true once the service has started shutting down
+func isDraining() bool {
+	return atomic.LoadInt32(&draining) == 1
+}
+
+// waitForShutdown : blocks until the service is asked to stop, then shuts it down and exits
+func waitForShutdown() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTERM, os.Interrupt)
+
+	s := <-sig
+	logger.Info("shutting down", logger.Fields{"signal": s.String(), "grace_period": gracePeriod.String()})
+
+	os.Exit(shutdown())
+}
+
+// shutdown : stops accepting new requests, then waits for the requests
+// already delivered to be handled, up to the grace period. Returns the exit
+// code of the process
+func shutdown() int {
+	code := 0
+
+	atomic.StoreInt32(&draining, 1)
+
+	// draining removes interest on the server, while messages already
+	// delivered to the client are still passed to the handlers, so none are lost
+	for _, sub := range subscriptions {
+		err := sub.Drain()
+		if err != nil {
+			logger.Error("could not drain subscription", logger.Fields{"subject": sub.Subject, "error": err})
+		}
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		// a subscription is only invalidated once its last callback has
+		// returned, so no request can be added once all are drained
+		for _, sub := range subscriptions {
+			for sub.IsValid() {
+				time.Sleep(DRAININTERVAL)
+			}
+		}
+
+		inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(gracePeriod):
+		logger.Error("grace period expired before all requests completed", logger.Fields{"grace_period": gracePeriod.String()})
+		code = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	for _, s := range servers {
+		_ = s.Shutdown(ctx)
+	}
+
+	n.Close()
+
+	return code
+}
@@ -0,0 +1,422 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package terraform
+
+import (
+	"strings"
+
+	aws "github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	"github.com/r3labs/graph"
+)
+
+// awsResource : returns the resource type a component is exported as, the
+// attribute holding its provider id and any related resources whose
+// values it provides
+func awsResource(c graph.Component) (string, string, []string) {
+	switch x := c.(type) {
+	case *aws.Vpc:
+		return "aws_vpc", "id", nil
+	case *aws.Network:
+		return "aws_subnet", "id", nil
+	case *aws.InternetGateway:
+		return "aws_internet_gateway", "id", nil
+	case *aws.NatGateway:
+		return "aws_nat_gateway", "id", nil
+	case *aws.SecurityGroup:
+		return "aws_security_group", "id", nil
+	case *aws.Instance:
+		if x.AssignElasticIP {
+			return "aws_instance", "id", []string{"aws_eip"}
+		}
+		return "aws_instance", "id", nil
+	case *aws.EBSVolume:
+		return "aws_ebs_volume", "id", nil
+	case *aws.ELB:
+		return "aws_elb", "name", nil
+	case *aws.RDSCluster:
+		return "aws_rds_cluster", "arn", nil
+	case *aws.RDSInstance:
+		if x.Cluster != "" {
+			return "aws_rds_cluster_instance", "arn", nil
+		}
+		return "aws_db_instance", "arn", nil
+	case *aws.Route53Zone:
+		return "aws_route53_zone", "zone_id", nil
+	case *aws.S3Bucket:
+		return "aws_s3_bucket", "bucket", nil
+	case *aws.IamRole:
+		return "aws_iam_role", "arn", nil
+	case *aws.IamPolicy:
+		return "aws_iam_policy", "arn", nil
+	case *aws.IamInstanceProfile:
+		return "aws_iam_instance_profile", "arn", nil
+	}
+
+	return "", "", nil
+}
+
+// awsAttribute : returns the resource attribute holding a templated
+// component field, along with the type of the related resource that holds
+// it, if it is not held by the component's own resource
+func awsAttribute(field string) (string, string, bool) {
+	switch field {
+	case "vpc_aws_id", "network_aws_id", "internet_gateway_aws_id", "security_group_aws_id", "instance_aws_id", "volume_aws_id":
+		return "", "id", true
+	case "iam_instance_profile_arn", "iam_policy_arn", "iam_role_arn":
+		return "", "arn", true
+	case "vpc_id", "public_ip", "dns_name", "endpoint":
+		return "", field, true
+	case "ip":
+		return "", "private_ip", true
+	case "elastic_ip":
+		return "aws_eip", "public_ip", true
+	}
+
+	return "", "", false
+}
+
+func (e *exporter) awsComponent(c graph.Component) {
+	switch x := c.(type) {
+	case *aws.Vpc:
+		b := e.resource(x, x.VpcAWSID)
+		b.set("cidr_block", x.Subnet)
+		b.set("tags", x.Tags)
+	case *aws.Network:
+		b := e.resource(x, x.NetworkAWSID)
+		b.set("vpc_id", e.value(x.VpcID))
+		b.set("cidr_block", x.Subnet)
+		b.set("availability_zone", x.AvailabilityZone)
+		b.set("map_public_ip_on_launch", x.IsPublic)
+		b.set("tags", x.Tags)
+	case *aws.InternetGateway:
+		b := e.resource(x, x.InternetGatewayAWSID)
+		b.set("vpc_id", e.value(x.VpcID))
+		b.set("tags", x.Tags)
+	case *aws.NatGateway:
+		e.awsNatGateway(x)
+	case *aws.SecurityGroup:
+		e.awsSecurityGroup(x)
+	case *aws.Instance:
+		e.awsInstance(x)
+	case *aws.EBSVolume:
+		b := e.resource(x, x.VolumeAWSID)
+		b.set("availability_zone", x.AvailabilityZone)
+		b.set("type", x.VolumeType)
+		b.set("size", x.Size)
+		b.set("iops", x.Iops)
+		b.set("encrypted", x.Encrypted)
+		b.set("kms_key_id", str(x.EncryptionKeyID))
+		b.set("tags", x.Tags)
+	case *aws.ELB:
+		e.awsELB(x)
+	case *aws.RDSCluster:
+		e.awsRDSCluster(x)
+	case *aws.RDSInstance:
+		e.awsRDSInstance(x)
+	case *aws.Route53Zone:
+		e.awsRoute53Zone(x)
+	case *aws.S3Bucket:
+		e.awsS3Bucket(x)
+	case *aws.IamRole:
+		b := e.resource(x, created(x.Name, x.IAMRoleARN))
+		b.set("name", x.Name)
+		b.set("path", x.Path)
+		b.set("description", x.Description)
+		b.set("assume_role_policy", x.AssumePolicyDocument)
+
+		for i, policy := range x.Policies {
+			var arn string
+			if i < len(x.PolicyARNs) {
+				arn = x.PolicyARNs[i]
+			}
+
+			pb, _ := e.related(x, "aws_iam_role_policy_attachment", label(x.Name+"_"+policy), created(x.Name+"/"+arn, x.IAMRoleARN, arn))
+			pb.set("role", e.byName(aws.TYPEIAMROLE, x.Name, "name"))
+			pb.set("policy_arn", e.value(arn))
+		}
+	case *aws.IamPolicy:
+		b := e.resource(x, x.IAMPolicyARN)
+		b.set("name", x.Name)
+		b.set("path", x.Path)
+		b.set("description", x.Description)
+		b.set("policy", x.PolicyDocument)
+	case *aws.IamInstanceProfile:
+		b := e.resource(x, created(x.Name, x.IAMInstanceProfileARN))
+		b.set("name", x.Name)
+		b.set("path", x.Path)
+
+		// terraform assigns a single role to an instance profile
+		if len(x.Roles) > 0 {
+			b.set("role", e.byName(aws.TYPEIAMROLE, x.Roles[0], "name"))
+		}
+	}
+}
+
+func (e *exporter) awsNatGateway(x *aws.NatGateway) {
+	// the elastic ip allocated to the nat gateway is adopted along with it
+	eb, eip := e.related(x, "aws_eip", label(x.Name), x.NatGatewayAllocationID)
+	eb.set("vpc", true)
+
+	b := e.resource(x, x.NatGatewayAWSID)
+	b.set("allocation_id", eip+".id")
+	b.set("subnet_id", e.value(x.PublicNetworkAWSID))
+	b.set("tags", x.Tags)
+}
+
+func (e *exporter) awsSecurityGroup(x *aws.SecurityGroup) {
+	b := e.resource(x, x.SecurityGroupAWSID)
+	b.set("name", x.Name)
+	b.set("vpc_id", e.value(x.VpcID))
+
+	for _, r := range x.Rules.Ingress {
+		awsRule(b.block("ingress"), r)
+	}
+
+	for _, r := range x.Rules.Egress {
+		awsRule(b.block("egress"), r)
+	}
+
+	b.set("tags", x.Tags)
+}
+
+func awsRule(b *body, r aws.SecurityGroupRule) {
+	from, to := r.From, r.To
+
+	switch {
+	case r.Protocol == "-1":
+		from, to = 0, 0
+	case to == 0:
+		to = 65535
+	}
+
+	b.setAlways("from_port", from)
+	b.setAlways("to_port", to)
+	b.setAlways("protocol", r.Protocol)
+	b.setAlways("cidr_blocks", []interface{}{r.IP})
+}
+
+func (e *exporter) awsInstance(x *aws.Instance) {
+	b := e.resource(x, x.InstanceAWSID)
+	b.set("ami", x.Image)
+	b.set("instance_type", x.Type)
+	b.set("subnet_id", e.value(x.NetworkAWSID))
+	b.set("private_ip", x.IP)
+	b.set("key_name", x.KeyPair)
+	b.set("user_data", x.UserData)
+	b.set("vpc_security_group_ids", e.values(x.SecurityGroupAWSIDs))
+
+	if x.IAMInstanceProfile != nil {
+		b.set("iam_instance_profile", e.byName(aws.TYPEIAMINSTANCEPROFILE, *x.IAMInstanceProfile, "name"))
+	}
+
+	b.set("tags", x.Tags)
+
+	instance := expression(e.addresses[x.GetID()] + ".id")
+
+	for _, v := range x.Volumes {
+		// attachments are imported as '<device>:<volume id>:<instance id>'
+		var id string
+		if providerID(v.VolumeAWSID) != "" && providerID(x.InstanceAWSID) != "" {
+			id = v.Device + ":" + v.VolumeAWSID + ":" + x.InstanceAWSID
+		}
+
+		ab, _ := e.related(x, "aws_volume_attachment", label(x.Name+"_"+v.Volume), id)
+		ab.set("device_name", v.Device)
+		ab.set("volume_id", e.value(v.VolumeAWSID))
+		ab.set("instance_id", instance)
+	}
+
+	if x.AssignElasticIP {
+		eb, _ := e.related(x, "aws_eip", label(x.Name), str(x.ElasticIPAWSID))
+		eb.set("vpc", true)
+		eb.set("instance", instance)
+	}
+}
+
+func (e *exporter) awsELB(x *aws.ELB) {
+	b := e.resource(x, created(x.Name, x.DNSName))
+	b.set("name", x.Name)
+	b.set("internal", x.IsPrivate)
+	b.set("subnets", e.values(x.NetworkAWSIDs))
+	b.set("security_groups", e.values(x.SecurityGroupAWSIDs))
+	b.set("instances", e.values(x.InstanceAWSIDs))
+
+	for _, l := range x.Listeners {
+		lb := b.block("listener")
+		lb.setAlways("lb_port", l.FromPort)
+		lb.setAlways("lb_protocol", strings.ToLower(l.Protocol))
+		lb.setAlways("instance_port", l.ToPort)
+		lb.setAlways("instance_protocol", strings.ToLower(l.Protocol))
+		lb.set("ssl_certificate_id", l.SSLCert)
+	}
+
+	b.set("tags", x.Tags)
+}
+
+func (e *exporter) awsRDSCluster(x *aws.RDSCluster) {
+	b := e.resource(x, created(x.Name, x.ARN))
+	b.set("cluster_identifier", x.Name)
+	b.set("engine", x.Engine)
+	b.set("engine_version", x.EngineVersion)
+	b.set("port", x.Port)
+	b.set("availability_zones", e.values(x.AvailabilityZones))
+	b.set("vpc_security_group_ids", e.values(x.SecurityGroupAWSIDs))
+	b.set("db_subnet_group_name", e.awsSubnetGroup(x, x.Name, x.NetworkAWSIDs))
+	b.set("database_name", x.DatabaseName)
+	b.set("master_username", x.DatabaseUsername)
+
+	if x.DatabasePassword != "" {
+		b.set("master_password", e.sensitive(x, "password", "master password of rds cluster "+x.Name))
+	}
+
+	b.set("backup_retention_period", x.BackupRetention)
+	b.set("preferred_backup_window", x.BackupWindow)
+	b.set("preferred_maintenance_window", x.MaintenanceWindow)
+	b.set("replication_source_identifier", x.ReplicationSource)
+	b.set("skip_final_snapshot", !x.FinalSnapshot)
+	b.set("tags", x.Tags)
+}
+
+func (e *exporter) awsRDSInstance(x *aws.RDSInstance) {
+	b := e.resource(x, created(x.Name, x.ARN))
+	b.set("identifier", x.Name)
+
+	if x.Cluster != "" {
+		b.set("cluster_identifier", e.byName(aws.TYPERDSCLUSTER, x.Cluster, "id"))
+		b.set("instance_class", x.Size)
+		b.set("engine", x.Engine)
+		b.set("engine_version", x.EngineVersion)
+		b.set("publicly_accessible", x.Public)
+		b.set("promotion_tier", x.PromotionTier)
+		b.set("db_subnet_group_name", e.awsSubnetGroup(x, x.Name, x.NetworkAWSIDs))
+		b.set("auto_minor_version_upgrade", x.AutoUpgrade)
+		b.set("preferred_maintenance_window", x.MaintenanceWindow)
+		b.set("tags", x.Tags)
+		return
+	}
+
+	b.set("instance_class", x.Size)
+	b.set("engine", x.Engine)
+	b.set("engine_version", x.EngineVersion)
+	b.set("port", x.Port)
+	b.set("allocated_storage", x.StorageSize)
+	b.set("storage_type", x.StorageType)
+	b.set("iops", x.StorageIops)
+	b.set("multi_az", x.MultiAZ)
+	b.set("publicly_accessible", x.Public)
+	b.set("availability_zone", x.AvailabilityZone)
+	b.set("vpc_security_group_ids", e.values(x.SecurityGroupAWSIDs))
+	b.set("db_subnet_group_name", e.awsSubnetGroup(x, x.Name, x.NetworkAWSIDs))
+	b.set("name", x.DatabaseName)
+	b.set("username", x.DatabaseUsername)
+
+	if x.DatabasePassword != "" {
+		b.set("password", e.sensitive(x, "password", "master password of rds instance "+x.Name))
+	}
+
+	b.set("auto_minor_version_upgrade", x.AutoUpgrade)
+	b.set("backup_retention_period", x.BackupRetention)
+	b.set("backup_window", x.BackupWindow)
+	b.set("maintenance_window", x.MaintenanceWindow)
+	b.set("replicate_source_db", x.ReplicationSource)
+	b.set("license_model", x.License)
+	b.set("timezone", x.Timezone)
+	b.set("skip_final_snapshot", !x.FinalSnapshot)
+	b.set("tags", x.Tags)
+}
+
+// awsSubnetGroup : adds the db subnet group of an rds cluster or instance,
+// returning a reference to its name. The names of subnet groups are not
+// part of a mapping, so they are never imported
+func (e *exporter) awsSubnetGroup(c graph.Component, name string, networks []string) interface{} {
+	if len(networks) < 1 {
+		return nil
+	}
+
+	b, address := e.related(c, "aws_db_subnet_group", label(name), "")
+	b.set("name", name)
+	b.set("subnet_ids", e.values(networks))
+
+	return address + ".name"
+}
+
+func (e *exporter) awsRoute53Zone(x *aws.Route53Zone) {
+	b := e.resource(x, x.HostedZoneID)
+	b.set("name", x.Name)
+
+	if x.Private {
+		vpc := e.value(x.VpcID)
+		if x.VpcID == "" {
+			vpc = e.byName(aws.TYPEVPC, x.Vpc, "id")
+		}
+
+		b.block("vpc").set("vpc_id", vpc)
+	}
+
+	b.set("tags", x.Tags)
+
+	zone := expression(e.addresses[x.GetID()] + ".zone_id")
+
+	for _, r := range x.Records {
+		// records are imported as '<zone id>_<name>_<type>'
+		var id string
+		if providerID(x.HostedZoneID) != "" {
+			id = x.HostedZoneID + "_" + r.Entry + "_" + r.Type
+		}
+
+		rb, _ := e.related(x, "aws_route53_record", label(r.Entry+"_"+strings.ToLower(r.Type)), id)
+		rb.set("zone_id", zone)
+		rb.set("name", r.Entry)
+		rb.set("type", r.Type)
+		rb.setAlways("ttl", r.TTL)
+		rb.set("records", e.values(r.Values))
+	}
+}
+
+func (e *exporter) awsS3Bucket(x *aws.S3Bucket) {
+	b := e.resource(x, created(x.Name, x.BucketURI))
+	b.set("bucket", x.Name)
+
+	if len(x.Grantees) < 1 {
+		b.set("acl", x.ACL)
+	}
+
+	for _, g := range x.Grantees {
+		gb := b.block("grant")
+
+		switch strings.ToLower(g.Type) {
+		case "uri", "group":
+			gb.set("type", "Group")
+			gb.set("uri", g.ID)
+		default:
+			gb.set("type", "CanonicalUser")
+			gb.set("id", g.ID)
+		}
+
+		gb.set("permissions", []interface{}{strings.ToUpper(g.Permissions)})
+	}
+
+	b.set("tags", x.Tags)
+}
+
+// created : returns the id a resource is imported by, if the value set
+// once its component has been created is known
+func created(id string, values ...string) string {
+	for _, v := range values {
+		if providerID(v) == "" {
+			return ""
+		}
+	}
+
+	return id
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,384 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package terraform
+
+import (
+	"strconv"
+	"strings"
+
+	azure "github.com/ernestio/definition-mapper/libmapper/providers/azure/components"
+	"github.com/r3labs/graph"
+)
+
+// azureResource : returns the resource type a component is exported as and
+// the attribute holding its provider id
+func azureResource(c graph.Component) (string, string, []string) {
+	switch c.(type) {
+	case *azure.ResourceGroup:
+		return "azurerm_resource_group", "id", nil
+	case *azure.VirtualNetwork:
+		return "azurerm_virtual_network", "id", nil
+	case *azure.Subnet:
+		return "azurerm_subnet", "id", nil
+	case *azure.SecurityGroup:
+		return "azurerm_network_security_group", "id", nil
+	case *azure.PublicIP:
+		return "azurerm_public_ip", "id", nil
+	case *azure.NetworkInterface:
+		return "azurerm_network_interface", "id", nil
+	case *azure.VirtualMachine:
+		return "azurerm_virtual_machine", "id", nil
+	case *azure.ManagedDisk:
+		return "azurerm_managed_disk", "id", nil
+	case *azure.AvailabilitySet:
+		return "azurerm_availability_set", "id", nil
+	case *azure.LB:
+		return "azurerm_lb", "id", nil
+	case *azure.LBBackendAddressPool:
+		return "azurerm_lb_backend_address_pool", "id", nil
+	case *azure.LBProbe:
+		return "azurerm_lb_probe", "id", nil
+	case *azure.LBRule:
+		return "azurerm_lb_rule", "id", nil
+	case *azure.SQLServer:
+		return "azurerm_sql_server", "id", nil
+	case *azure.SQLDatabase:
+		return "azurerm_sql_database", "id", nil
+	case *azure.SQLFirewallRule:
+		return "azurerm_sql_firewall_rule", "id", nil
+	case *azure.StorageAccount:
+		return "azurerm_storage_account", "id", nil
+	case *azure.StorageContainer:
+		return "azurerm_storage_container", "id", nil
+	}
+
+	return "", "", nil
+}
+
+// azureAttribute : returns the resource attribute holding a templated component field
+func azureAttribute(field string) (string, string, bool) {
+	return "", field, field == "id"
+}
+
+func (e *exporter) azureComponent(c graph.Component) {
+	// every azure resource is imported by its resource id
+	b := e.resource(c, c.GetProviderID())
+
+	switch x := c.(type) {
+	case *azure.ResourceGroup:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("tags", x.Tags)
+	case *azure.VirtualNetwork:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("address_space", e.values(x.AddressSpace))
+		b.set("dns_servers", e.values(x.DNSServerNames))
+		b.set("tags", x.Tags)
+	case *azure.Subnet:
+		b.set("name", x.Name)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("virtual_network_name", e.byName(azure.TYPEVIRTUALNETWORK, x.VirtualNetworkName, "name"))
+		b.set("address_prefix", x.AddressPrefix)
+		b.set("network_security_group_id", e.id(x.NetworkSecurityGroupID, azure.TYPESECURITYGROUP, x.NetworkSecurityGroup))
+	case *azure.SecurityGroup:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+
+		for _, r := range x.SecurityRules {
+			rb := b.block("security_rule")
+			rb.set("name", r.Name)
+			rb.set("description", r.Description)
+			rb.setAlways("priority", r.Priority)
+			rb.set("direction", r.Direction)
+			rb.set("access", r.Access)
+			rb.set("protocol", r.Protocol)
+			rb.set("source_port_range", r.SourcePort)
+			rb.set("destination_port_range", r.DestinationPortRange)
+			rb.set("source_address_prefix", r.SourceAddressPrefix)
+			rb.set("destination_address_prefix", r.DestinationAddressPrefix)
+		}
+
+		b.set("tags", x.Tags)
+	case *azure.PublicIP:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("allocation_method", title(x.PublicIPAddressAllocation))
+		b.set("tags", x.Tags)
+	case *azure.NetworkInterface:
+		e.azureNetworkInterface(b, x)
+	case *azure.VirtualMachine:
+		e.azureVirtualMachine(b, x)
+	case *azure.ManagedDisk:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("storage_account_type", x.StorageAccountType)
+		b.set("create_option", x.CreateOption)
+		b.set("disk_size_gb", x.DiskSizeGB)
+		b.set("os_type", x.OSType)
+		b.set("source_uri", x.SourceURI)
+		b.set("source_resource_id", e.value(x.SourceResourceID))
+		b.set("tags", x.Tags)
+	case *azure.AvailabilitySet:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("platform_fault_domain_count", x.PlatformFaultDomainCount)
+		b.set("platform_update_domain_count", x.PlatformUpdateDomainCount)
+		b.set("managed", x.Managed)
+		b.set("tags", x.Tags)
+	case *azure.LB:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+
+		for _, f := range x.FrontendIPConfigurations {
+			fb := b.block("frontend_ip_configuration")
+			fb.set("name", f.Name)
+			fb.set("subnet_id", e.id(f.SubnetID, azure.TYPESUBNET, f.Subnet))
+			fb.set("private_ip_address", f.PrivateIPAddress)
+			fb.set("private_ip_address_allocation", title(f.PrivateIPAddressAllocation))
+			fb.set("public_ip_address_id", e.id(f.PublicIPAddressID, azure.TYPEPUBLICIP, f.PublicIPAddress))
+		}
+
+		b.set("tags", x.Tags)
+	case *azure.LBBackendAddressPool:
+		b.set("name", x.Name)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("loadbalancer_id", e.id(x.LoadbalancerID, azure.TYPELB, x.Loadbalancer))
+	case *azure.LBProbe:
+		b.set("name", x.Name)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("loadbalancer_id", e.id(x.LoadbalancerID, azure.TYPELB, x.Loadbalancer))
+		b.set("protocol", x.Protocol)
+		b.setAlways("port", x.Port)
+		b.set("request_path", x.RequestPath)
+		b.set("interval_in_seconds", x.IntervalInSeconds)
+		b.set("number_of_probes", x.NumberOfProbes)
+	case *azure.LBRule:
+		b.set("name", x.Name)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("loadbalancer_id", e.id(x.LoadbalancerID, azure.TYPELB, x.Loadbalancer))
+		b.set("protocol", x.Protocol)
+		b.setAlways("frontend_port", x.FrontendPort)
+		b.setAlways("backend_port", x.BackendPort)
+		b.set("frontend_ip_configuration_name", x.FrontendIPConfigurationName)
+		b.set("backend_address_pool_id", e.id(x.BackendAddressPoolID, azure.TYPELBBACKENDADDRESSPOOL, x.BackendAddressPool))
+		b.set("probe_id", e.id(x.ProbeID, azure.TYPELBPROBE, x.Probe))
+		b.set("enable_floating_ip", x.EnableFloatingIP)
+		b.set("idle_timeout_in_minutes", x.IdleTimeoutInMinutes)
+		b.set("load_distribution", x.LoadDistribution)
+	case *azure.SQLServer:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("version", x.Version)
+		b.set("administrator_login", x.AdministratorLogin)
+
+		if x.AdministratorLoginPassword != "" {
+			b.set("administrator_login_password", e.sensitive(x, "password", "administrator password of sql server "+x.Name))
+		}
+
+		b.set("tags", x.Tags)
+	case *azure.SQLDatabase:
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("server_name", e.byName(azure.TYPESQLSERVER, x.ServerName, "name"))
+		b.set("edition", x.Edition)
+		b.set("collation", x.Collation)
+		b.set("create_mode", x.CreateMode)
+		b.set("max_size_bytes", x.MaxSizeBytes)
+		b.set("requested_service_objective_name", x.RequestedServiceObjectiveName)
+		b.set("tags", x.Tags)
+	case *azure.SQLFirewallRule:
+		b.set("name", x.Name)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("server_name", e.byName(azure.TYPESQLSERVER, x.ServerName, "name"))
+		b.set("start_ip_address", x.StartIPAddress)
+		b.set("end_ip_address", x.EndIPAddress)
+	case *azure.StorageAccount:
+		// account types combine the tier and replication type, i.e. Standard_LRS
+		parts := strings.SplitN(x.AccountType, "_", 2)
+
+		b.set("name", x.Name)
+		b.set("location", x.Location)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("account_kind", x.AccountKind)
+		b.set("account_tier", parts[0])
+		if len(parts) > 1 {
+			b.set("account_replication_type", parts[1])
+		}
+		b.set("enable_blob_encryption", x.EnableBlobEncryption)
+		b.set("tags", x.Tags)
+	case *azure.StorageContainer:
+		b.set("name", x.Name)
+		b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+		b.set("storage_account_name", e.byName(azure.TYPESTORAGEACCOUNT, x.StorageAccountName, "name"))
+		b.set("container_access_type", x.ContainerAccessType)
+	}
+}
+
+func (e *exporter) azureNetworkInterface(b *body, x *azure.NetworkInterface) {
+	b.set("name", x.Name)
+	b.set("location", x.Location)
+	b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+	b.set("network_security_group_id", e.id(x.NetworkSecurityGroupID, azure.TYPESECURITYGROUP, x.NetworkSecurityGroup))
+	b.set("enable_ip_forwarding", x.EnableIPForwarding)
+	b.set("dns_servers", e.values(x.DNSServers))
+	b.set("internal_dns_name_label", x.InternalDNSNameLabel)
+
+	for _, ip := range x.IPConfigurations {
+		ib := b.block("ip_configuration")
+		ib.set("name", ip.Name)
+		ib.set("subnet_id", e.id(ip.SubnetID, azure.TYPESUBNET, ip.Subnet))
+		ib.set("private_ip_address", ip.PrivateIPAddress)
+		ib.set("private_ip_address_allocation", title(ip.PrivateIPAddressAllocation))
+		ib.set("public_ip_address_id", e.id(ip.PublicIPAddressID, azure.TYPEPUBLICIP, ip.PublicIPAddress))
+		ib.set("load_balancer_backend_address_pools_ids", e.ids(ip.LoadBalancerBackendAddressPoolIDs, azure.TYPELBBACKENDADDRESSPOOL, ip.LoadBalancerBackendAddressPools))
+	}
+
+	b.set("tags", x.Tags)
+}
+
+func (e *exporter) azureVirtualMachine(b *body, x *azure.VirtualMachine) {
+	b.set("name", x.Name)
+	b.set("location", x.Location)
+	b.set("resource_group_name", e.resourceGroup(x.ResourceGroupName))
+	b.set("vm_size", x.VMSize)
+	b.set("availability_set_id", e.id(x.AvailabilitySetID, azure.TYPEAVAILABILITYSET, x.AvailabilitySet))
+	b.set("network_interface_ids", e.ids(x.NetworkInterfaceIDs, azure.TYPENETWORKINTERFACE, x.NetworkInterfaces))
+	b.set("license_type", x.LicenseType)
+	b.set("delete_os_disk_on_termination", x.DeleteOSDiskOnTermination)
+	b.set("delete_data_disks_on_termination", x.DeleteDataDisksOnTermination)
+
+	if x.StorageImageReference.Publisher != "" {
+		ib := b.block("storage_image_reference")
+		ib.set("publisher", x.StorageImageReference.Publisher)
+		ib.set("offer", x.StorageImageReference.Offer)
+		ib.set("sku", x.StorageImageReference.Sku)
+		ib.set("version", x.StorageImageReference.Version)
+	}
+
+	ob := b.block("storage_os_disk")
+	ob.set("name", x.StorageOSDisk.Name)
+	ob.set("caching", x.StorageOSDisk.Caching)
+	ob.set("create_option", x.StorageOSDisk.CreateOption)
+	ob.set("managed_disk_type", x.StorageOSDisk.StorageAccountType)
+	ob.set("managed_disk_id", e.id(x.StorageOSDisk.ManagedDiskID, azure.TYPEMANAGEDDISK, x.StorageOSDisk.ManagedDisk))
+	ob.set("vhd_uri", x.StorageOSDisk.VhdURI)
+	ob.set("image_uri", x.StorageOSDisk.ImageURI)
+	ob.set("os_type", x.StorageOSDisk.OSType)
+
+	if x.StorageDataDisk.Name != "" {
+		db := b.block("storage_data_disk")
+		db.set("name", x.StorageDataDisk.Name)
+		db.set("create_option", x.StorageDataDisk.CreateOption)
+		db.setAlways("lun", lun(x.StorageDataDisk.Lun))
+		db.set("disk_size_gb", x.StorageDataDisk.Size)
+		db.set("managed_disk_type", x.StorageDataDisk.StorageAccountType)
+		db.set("managed_disk_id", e.id(x.StorageDataDisk.ManagedDiskID, azure.TYPEMANAGEDDISK, x.StorageDataDisk.ManagedDisk))
+		db.set("vhd_uri", x.StorageDataDisk.VhdURI)
+	}
+
+	pb := b.block("os_profile")
+	pb.set("computer_name", x.OSProfile.ComputerName)
+	pb.set("admin_username", x.OSProfile.AdminUsername)
+
+	if x.OSProfile.AdminPassword != "" {
+		pb.set("admin_password", e.sensitive(x, "admin_password", "admin password of virtual machine "+x.Name))
+	}
+
+	pb.set("custom_data", x.OSProfile.CustomData)
+
+	if x.OSProfileWindowsConfig != nil {
+		wb := b.block("os_profile_windows_config")
+		wb.set("provision_vm_agent", x.OSProfileWindowsConfig.ProvisionVMAgent)
+		wb.set("enable_automatic_upgrades", x.OSProfileWindowsConfig.EnableAutomaticUpgrades)
+	} else {
+		lb := b.block("os_profile_linux_config")
+		lb.setAlways("disable_password_authentication", x.OSProfileLinuxConfig.DisablePasswordAuthentication != nil && *x.OSProfileLinuxConfig.DisablePasswordAuthentication)
+
+		for _, k := range x.OSProfileLinuxConfig.SSHKeys {
+			kb := lb.block("ssh_keys")
+			kb.set("path", k.Path)
+			kb.set("key_data", k.KeyData)
+		}
+	}
+
+	for _, d := range x.BootDiagnostics {
+		db := b.block("boot_diagnostics")
+		db.setAlways("enabled", d.Enabled)
+		db.set("storage_uri", d.URI)
+	}
+
+	if x.Plan.Name != "" {
+		pb := b.block("plan")
+		pb.set("name", x.Plan.Name)
+		pb.set("publisher", x.Plan.Publisher)
+		pb.set("product", x.Plan.Product)
+	}
+
+	b.set("tags", x.Tags)
+}
+
+// resourceGroup : returns a reference to the name of a resource group
+func (e *exporter) resourceGroup(name string) interface{} {
+	return e.byName(azure.TYPERESOURCEGROUP, name, "name")
+}
+
+// id : translates a resource id, or references the component it names if the id is not set
+func (e *exporter) id(id, ctype, name string) interface{} {
+	if id != "" {
+		return e.value(id)
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	return e.byName(ctype, name, "id")
+}
+
+// ids : translates a list of resource ids, or references the components they name if no ids are set
+func (e *exporter) ids(ids []string, ctype string, names []string) []interface{} {
+	if len(ids) > 0 {
+		return e.values(ids)
+	}
+
+	var v []interface{}
+
+	for _, name := range names {
+		v = append(v, e.byName(ctype, name, "id"))
+	}
+
+	return v
+}
+
+// title : capitalises an allocation method, as terraform expects i.e. Static or Dynamic
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+}
+
+func lun(s string) interface{} {
+	if s == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return s
+	}
+
+	return n
+}
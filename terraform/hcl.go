@@ -0,0 +1,248 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// expression : a raw hcl expression, such as a reference to another resource
+type expression string
+
+// body : the attributes and nested blocks of a block, kept in the order they are set
+type body struct {
+	items []item
+}
+
+type item struct {
+	key   string
+	value interface{}
+	block *body
+}
+
+// set : sets an attribute, unless its value is empty, false or zero
+func (b *body) set(key string, value interface{}) {
+	if isEmpty(value) {
+		return
+	}
+
+	b.items = append(b.items, item{key: key, value: value})
+}
+
+// setAlways : sets an attribute, regardless of its value
+func (b *body) setAlways(key string, value interface{}) {
+	b.items = append(b.items, item{key: key, value: value})
+}
+
+// block : appends a nested block
+func (b *body) block(key string) *body {
+	nb := &body{}
+	b.items = append(b.items, item{key: key, block: nb})
+	return nb
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case expression:
+		return v == ""
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case int32:
+		return v == 0
+	case int64:
+		return v == 0
+	case *int64:
+		return v == nil
+	case *int32:
+		return v == nil
+	case *bool:
+		return v == nil
+	case []interface{}:
+		return len(v) == 0
+	case map[string]string:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// write : writes the body's items at the given indentation. The equals signs
+// of consecutive single line attributes are aligned, as terraform fmt does
+func (b *body) write(buf *bytes.Buffer, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	widths := b.widths()
+
+	for i := 0; i < len(b.items); i++ {
+		it := b.items[i]
+
+		if it.block != nil {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+
+			if len(it.block.items) < 1 {
+				buf.WriteString(prefix + it.key + " {}\n")
+			} else {
+				buf.WriteString(prefix + it.key + " {\n")
+				it.block.write(buf, indent+1)
+				buf.WriteString(prefix + "}\n")
+			}
+
+			if i+1 < len(b.items) && b.items[i+1].block == nil {
+				buf.WriteString("\n")
+			}
+
+			continue
+		}
+
+		buf.WriteString(prefix + it.key + strings.Repeat(" ", widths[i]-len(it.key)) + " = " + value(it.value, indent) + "\n")
+	}
+}
+
+// widths : returns the width each attribute's key is padded to. Consecutive
+// single line attributes share the width of their longest key, while blocks
+// and multi line values break the run
+func (b *body) widths() []int {
+	widths := make([]int, len(b.items))
+
+	start := 0
+	for i := 0; i <= len(b.items); i++ {
+		if i < len(b.items) && b.items[i].block == nil && !multiline(b.items[i].value) {
+			continue
+		}
+
+		width := 0
+		for j := start; j < i; j++ {
+			if len(b.items[j].key) > width {
+				width = len(b.items[j].key)
+			}
+		}
+
+		for j := start; j < i; j++ {
+			widths[j] = width
+		}
+
+		if i < len(b.items) {
+			widths[i] = len(b.items[i].key)
+		}
+
+		start = i + 1
+	}
+
+	return widths
+}
+
+func multiline(v interface{}) bool {
+	m, ok := v.(map[string]string)
+	return ok && len(m) > 0
+}
+
+// value : renders a value as an hcl expression
+func value(v interface{}, indent int) string {
+	switch x := v.(type) {
+	case expression:
+		return string(x)
+	case string:
+		return quote(x)
+	case bool:
+		return fmt.Sprint(x)
+	case int, int32, int64:
+		return fmt.Sprint(x)
+	case *int64:
+		return fmt.Sprint(*x)
+	case *int32:
+		return fmt.Sprint(*x)
+	case *bool:
+		return fmt.Sprint(*x)
+	case []interface{}:
+		values := make([]string, len(x))
+		for i := range x {
+			values[i] = value(x[i], indent)
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case map[string]string:
+		return mapValue(x, indent)
+	}
+
+	return quote(fmt.Sprint(v))
+}
+
+func mapValue(m map[string]string, indent int) string {
+	var keys []string
+
+	width := 0
+	for k := range m {
+		keys = append(keys, k)
+		if len(mapKey(k)) > width {
+			width = len(mapKey(k))
+		}
+	}
+
+	sort.Strings(keys)
+
+	prefix := strings.Repeat("  ", indent+1)
+	lines := []string{"{"}
+
+	for _, k := range keys {
+		key := mapKey(k)
+		lines = append(lines, prefix+key+strings.Repeat(" ", width-len(key))+" = "+quote(m[k]))
+	}
+
+	lines = append(lines, strings.Repeat("  ", indent)+"}")
+
+	return strings.Join(lines, "\n")
+}
+
+func mapKey(k string) string {
+	if identifier.MatchString(k) {
+		return k
+	}
+
+	return quote(k)
+}
+
+// quote : renders a string literal, escaping any sequences terraform would interpolate
+func quote(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	)
+
+	return `"` + r.Replace(s) + `"`
+}
+
+// label : returns a valid resource name for a component name
+func label(name string) string {
+	l := []byte(name)
+
+	for i, c := range l {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			l[i] = '_'
+		}
+	}
+
+	if len(l) < 1 || (l[0] >= '0' && l[0] <= '9') || l[0] == '-' {
+		l = append([]byte{'_'}, l...)
+	}
+
+	return string(l)
+}
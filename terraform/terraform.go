@@ -0,0 +1,365 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package terraform
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/r3labs/graph"
+)
+
+// PROVIDERS : the terraform providers graphs can be exported to, by mapper
+// provider. Resources are written for the provider versions whose schemas
+// match ernest's components
+var PROVIDERS = map[string]Provider{
+	"aws":   {Name: "aws", Source: "hashicorp/aws", Version: "~> 3.0"},
+	"azure": {Name: "azurerm", Source: "hashicorp/azurerm", Version: "~> 1.44"},
+}
+
+// Provider : a terraform provider
+type Provider struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version"`
+}
+
+var template = regexp.MustCompile(`\$\(components\.#\[_component_id="([^"]+)"\]\.([A-Za-z0-9_]+)\)`)
+
+// Export : the terraform configuration of a graph
+type Export struct {
+	Provider  Provider    `json:"provider"`
+	Resources []*Resource `json:"resources"`
+	Variables []Variable  `json:"variables"`
+	Imports   []Import    `json:"imports"`
+	Skipped   []string    `json:"skipped,omitempty"`
+	region    string
+}
+
+// Resource : a terraform resource, converted from a component
+type Resource struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	ComponentID string `json:"component_id"`
+	body        *body
+}
+
+// Variable : a sensitive value that is not exported, such as a database
+// password, which must be set on the terraform configuration instead
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Import : adopts an existing resource into terraform's state, by its provider id
+type Import struct {
+	To string `json:"to"`
+	ID string `json:"id"`
+}
+
+// Supported : returns true if graphs of a provider can be exported
+func Supported(provider string) bool {
+	_, ok := PROVIDERS[provider]
+	return ok
+}
+
+// New : converts a loaded graph into terraform resources. References
+// between components, set as templates or as provider ids, are translated
+// into references between resources. Components that exist on the provider
+// are imported by their provider ids, so terraform can adopt them instead
+// of creating them again. Components that cannot be exported are skipped
+func New(g *graph.Graph, provider string) (*Export, error) {
+	tp, ok := PROVIDERS[provider]
+	if !ok {
+		return nil, errors.New("graphs of provider '" + provider + "' cannot be exported to terraform")
+	}
+
+	e := exporter{
+		addresses: make(map[string]string),
+		byID:      make(map[string]string),
+		taken:     make(map[string]bool),
+		export: &Export{
+			Provider:  tp,
+			Resources: []*Resource{},
+			Variables: []Variable{},
+			Imports:   []Import{},
+		},
+	}
+
+	switch provider {
+	case "aws":
+		e.resources = awsResource
+		e.attribute = awsAttribute
+		e.convert = e.awsComponent
+	case "azure":
+		e.resources = azureResource
+		e.attribute = azureAttribute
+		e.convert = e.azureComponent
+	}
+
+	// addresses are assigned first, as components may reference any other component
+	for _, c := range g.Components {
+		rt, attr, related := e.resources(c)
+
+		switch {
+		case c.GetType() == "credentials":
+			if gc, ok := c.(*graph.GenericComponent); ok && provider == "aws" {
+				e.export.region, _ = (*gc)["region"].(string)
+			}
+			continue
+		case rt == "":
+			e.export.Skipped = append(e.export.Skipped, c.GetID())
+			continue
+		}
+
+		e.addresses[c.GetID()] = rt + "." + e.name(rt, label(c.GetName()))
+
+		for _, rrt := range related {
+			e.addresses[c.GetID()+"|"+rrt] = rrt + "." + e.name(rrt, label(c.GetName()))
+		}
+
+		if id := providerID(c.GetProviderID()); id != "" {
+			e.byID[id] = e.addresses[c.GetID()] + "." + attr
+		}
+	}
+
+	for _, c := range g.Components {
+		if e.addresses[c.GetID()] != "" {
+			e.convert(c)
+		}
+	}
+
+	return e.export, nil
+}
+
+// exporter : the state of a graph being exported
+type exporter struct {
+	export    *Export
+	addresses map[string]string
+	byID      map[string]string
+	taken     map[string]bool
+	resources func(graph.Component) (string, string, []string)
+	attribute func(field string) (string, string, bool)
+	convert   func(graph.Component)
+}
+
+// name : returns a resource name that is unique for the resource type
+func (e *exporter) name(rt, name string) string {
+	n := name
+
+	for i := 2; e.taken[rt+"."+n]; i++ {
+		n = name + "_" + strconv.Itoa(i)
+	}
+
+	e.taken[rt+"."+n] = true
+
+	return n
+}
+
+// resource : adds the resource a component is exported as, importing it if it
+// exists on the provider
+func (e *exporter) resource(c graph.Component, importID string) *body {
+	address := e.addresses[c.GetID()]
+	parts := strings.SplitN(address, ".", 2)
+
+	return e.add(parts[0], parts[1], c.GetID(), importID)
+}
+
+// related : adds a resource that is part of a component, such as the
+// attachment of a volume to an instance, returning its address
+func (e *exporter) related(c graph.Component, rt, name, importID string) (*body, expression) {
+	address, ok := e.addresses[c.GetID()+"|"+rt]
+	if !ok {
+		address = rt + "." + e.name(rt, name)
+	}
+
+	parts := strings.SplitN(address, ".", 2)
+
+	return e.add(parts[0], parts[1], c.GetID(), importID), expression(address)
+}
+
+func (e *exporter) add(rt, name, componentID, importID string) *body {
+	r := Resource{
+		Type:        rt,
+		Name:        name,
+		ComponentID: componentID,
+		body:        &body{},
+	}
+
+	e.export.Resources = append(e.export.Resources, &r)
+
+	if id := providerID(importID); id != "" {
+		e.export.Imports = append(e.export.Imports, Import{To: rt + "." + name, ID: id})
+	}
+
+	return r.body
+}
+
+// sensitive : returns a reference to a variable holding a sensitive value
+// of a component, which is not exported
+func (e *exporter) sensitive(c graph.Component, key, description string) expression {
+	name := strings.Replace(e.addresses[c.GetID()], ".", "_", -1) + "_" + key
+
+	e.export.Variables = append(e.export.Variables, Variable{Name: name, Description: description})
+
+	return expression("var." + name)
+}
+
+// value : translates a component value into an hcl value. Templated values
+// and the provider ids of exported components become references to their
+// resources. Templates referencing other values, such as credentials, are
+// kept as they are
+func (e *exporter) value(s string) interface{} {
+	if address, ok := e.byID[s]; ok {
+		return expression(address)
+	}
+
+	matches := template.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) < 1 {
+		return s
+	}
+
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		if ref, ok := e.reference(s[matches[0][2]:matches[0][3]], s[matches[0][4]:matches[0][5]]); ok {
+			return expression(ref)
+		}
+
+		return s
+	}
+
+	// templates within a larger value are interpolated
+	var parts []string
+
+	last := 0
+	for _, m := range matches {
+		ref, ok := e.reference(s[m[2]:m[3]], s[m[4]:m[5]])
+		if !ok {
+			return s
+		}
+
+		literal := quote(s[last:m[0]])
+		parts = append(parts, literal[1:len(literal)-1], "${"+ref+"}")
+		last = m[1]
+	}
+
+	literal := quote(s[last:])
+	parts = append(parts, literal[1:len(literal)-1])
+
+	return expression(`"` + strings.Join(parts, "") + `"`)
+}
+
+// values : translates a list of component values
+func (e *exporter) values(s []string) []interface{} {
+	var v []interface{}
+
+	for i := range s {
+		v = append(v, e.value(s[i]))
+	}
+
+	return v
+}
+
+// byName : returns a reference to an attribute of the component of a type and
+// name, or the name itself if the component is not exported
+func (e *exporter) byName(ctype, name, attribute string) interface{} {
+	if address, ok := e.addresses[ctype+"::"+name]; ok && name != "" {
+		return expression(address + "." + attribute)
+	}
+
+	return name
+}
+
+// reference : returns the resource attribute a templated value refers to
+func (e *exporter) reference(componentID, field string) (string, bool) {
+	address, ok := e.addresses[componentID]
+	if !ok {
+		return "", false
+	}
+
+	rt, attribute, ok := e.attribute(field)
+	if !ok {
+		return "", false
+	}
+
+	if rt != "" {
+		// the value is held by a related resource of the component
+		address, ok = e.addresses[componentID+"|"+rt]
+		if !ok {
+			return "", false
+		}
+	}
+
+	return address + "." + attribute, true
+}
+
+// providerID : returns a provider id, or an empty string if the component
+// has not been created or the id is still templated
+func providerID(id string) string {
+	if strings.HasPrefix(id, "$(") {
+		return ""
+	}
+
+	return id
+}
+
+// HCL : renders the export as terraform configuration. Import blocks require terraform 1.5 or later
+func (e *Export) HCL() string {
+	var buf bytes.Buffer
+
+	tb := &body{}
+	tb.block("required_providers").setAlways(e.Provider.Name, map[string]string{
+		"source":  e.Provider.Source,
+		"version": e.Provider.Version,
+	})
+
+	writeBlock(&buf, "terraform", tb)
+
+	pb := &body{}
+	pb.set("region", e.region)
+
+	writeBlock(&buf, `provider "`+e.Provider.Name+`"`, pb)
+
+	for _, v := range e.Variables {
+		vb := &body{}
+		vb.set("description", v.Description)
+		vb.setAlways("type", expression("string"))
+		vb.setAlways("sensitive", true)
+
+		writeBlock(&buf, `variable "`+v.Name+`"`, vb)
+	}
+
+	for _, r := range e.Resources {
+		writeBlock(&buf, `resource "`+r.Type+`" "`+r.Name+`"`, r.body)
+	}
+
+	for _, i := range e.Imports {
+		ib := &body{}
+		ib.setAlways("to", expression(i.To))
+		ib.setAlways("id", i.ID)
+
+		writeBlock(&buf, "import", ib)
+	}
+
+	return buf.String()
+}
+
+// writeBlock : writes a top level block, separated from the previous block by an empty line
+func writeBlock(buf *bytes.Buffer, header string, b *body) {
+	if buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+
+	if len(b.items) < 1 {
+		buf.WriteString(header + " {}\n")
+		return
+	}
+
+	buf.WriteString(header + " {\n")
+	b.write(buf, 1)
+	buf.WriteString("}\n")
+}
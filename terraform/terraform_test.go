@@ -0,0 +1,144 @@
+package terraform_test
+
+import (
+	"testing"
+
+	"github.com/ernestio/definition-mapper/libmapper/providers/aws/components"
+	azure "github.com/ernestio/definition-mapper/libmapper/providers/azure/components"
+	"github.com/ernestio/definition-mapper/terraform"
+	"github.com/ernestio/ernestprovider/types/azure/networkinterface"
+	"github.com/r3labs/graph"
+	"github.com/stretchr/testify/suite"
+)
+
+// TerraformTestSuite : Test suite for terraform exports
+type TerraformTestSuite struct {
+	suite.Suite
+}
+
+// TestNew : Testing the export of an aws graph
+func (suite *TerraformTestSuite) TestNew() {
+	web := &components.Instance{Name: "web-1", Type: "t2.micro", UserData: "echo ${HOME}", Network: "public", InstanceAWSID: "i-0001"}
+
+	g := graph.New()
+	for _, c := range []graph.Component{
+		&components.Vpc{Name: "main", Subnet: "10.0.0.0/16", VpcAWSID: "vpc-0001"},
+		&components.Network{Name: "public", Subnet: "10.0.1.0/24", VpcID: "vpc-0001"},
+		web,
+		&components.RDSInstance{Name: "db", Size: "db.t2.micro", DatabasePassword: "secret"},
+	} {
+		c.SetDefaultVariables()
+		g.AddComponent(c)
+	}
+
+	// the instance's network is set as a template referencing the network
+	web.Rebuild(g)
+
+	e, err := terraform.New(g, "aws")
+	suite.Nil(err)
+	suite.Equal("aws", e.Provider.Name)
+	suite.Len(e.Resources, 4)
+	suite.Equal([]terraform.Import{
+		{To: "aws_vpc.main", ID: "vpc-0001"},
+		{To: "aws_instance.web-1", ID: "i-0001"},
+	}, e.Imports)
+	suite.Len(e.Variables, 1)
+	suite.Equal("aws_db_instance_db_password", e.Variables[0].Name)
+
+	hcl := e.HCL()
+	suite.Contains(hcl, "vpc_id     = aws_vpc.main.id\n")
+	suite.Contains(hcl, "subnet_id     = aws_subnet.public.id\n")
+	suite.Contains(hcl, `user_data     = "echo $${HOME}"`)
+	suite.Contains(hcl, "password            = var.aws_db_instance_db_password\n")
+	suite.NotContains(hcl, "secret")
+	suite.Contains(hcl, "import {\n  to = aws_instance.web-1\n  id = \"i-0001\"\n}\n")
+}
+
+// TestNewAzure : Testing the export of an azure graph
+func (suite *TerraformTestSuite) TestNewAzure() {
+	rg := &azure.ResourceGroup{}
+	rg.Name = "rg"
+	rg.Location = "westeurope"
+	rg.ID = "/subscriptions/s-1/resourceGroups/rg"
+
+	vnet := &azure.VirtualNetwork{}
+	vnet.Name = "vnet"
+	vnet.Location = "westeurope"
+	vnet.ResourceGroupName = "rg"
+	vnet.AddressSpace = []string{"10.0.0.0/16"}
+
+	subnet := &azure.Subnet{}
+	subnet.Name = "web"
+	subnet.ResourceGroupName = "rg"
+	subnet.VirtualNetworkName = "vnet"
+	subnet.AddressPrefix = "10.0.1.0/24"
+
+	nic := &azure.NetworkInterface{}
+	nic.Name = "web-1"
+	nic.Location = "westeurope"
+	nic.ResourceGroupName = "rg"
+	nic.IPConfigurations = []networkinterface.IPConfiguration{{Name: "ip", Subnet: "web", PrivateIPAddressAllocation: "dynamic"}}
+
+	vm := &azure.VirtualMachine{}
+	vm.Name = "web-1"
+	vm.Location = "westeurope"
+	vm.ResourceGroupName = "rg"
+	vm.VMSize = "Standard_DS1_v2"
+	vm.NetworkInterfaces = []string{"web-1"}
+	vm.OSProfile.AdminUsername = "ernest"
+	vm.OSProfile.AdminPassword = "secret"
+	vm.ID = "/subscriptions/s-1/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/web-1"
+
+	sql := &azure.SQLServer{}
+	sql.Name = "db"
+	sql.Location = "westeurope"
+	sql.ResourceGroupName = "rg"
+	sql.AdministratorLogin = "ernest"
+	sql.AdministratorLoginPassword = "secret"
+
+	g := graph.New()
+	for _, c := range []graph.Component{rg, vnet, subnet, nic, vm, sql} {
+		c.SetDefaultVariables()
+		g.AddComponent(c)
+	}
+
+	// network interfaces and subnets are set as templates referencing their components
+	for _, c := range g.Components {
+		c.Rebuild(g)
+	}
+
+	e, err := terraform.New(g, "azure")
+	suite.Nil(err)
+	suite.Equal("azurerm", e.Provider.Name)
+	suite.Len(e.Resources, 6)
+	suite.Equal([]terraform.Import{
+		{To: "azurerm_resource_group.rg", ID: rg.ID},
+		{To: "azurerm_virtual_machine.web-1", ID: vm.ID},
+	}, e.Imports)
+	suite.Len(e.Variables, 2)
+	suite.Equal("azurerm_virtual_machine_web-1_admin_password", e.Variables[0].Name)
+	suite.Equal("azurerm_sql_server_db_password", e.Variables[1].Name)
+
+	hcl := e.HCL()
+	suite.Contains(hcl, "resource_group_name = azurerm_resource_group.rg.name\n")
+	suite.Contains(hcl, "virtual_network_name = azurerm_virtual_network.vnet.name\n")
+	suite.Contains(hcl, "subnet_id                     = azurerm_subnet.web.id\n")
+	suite.Contains(hcl, "private_ip_address_allocation = \"Dynamic\"\n")
+	suite.Contains(hcl, "network_interface_ids = [azurerm_network_interface.web-1.id]\n")
+	suite.Contains(hcl, "admin_password = var.azurerm_virtual_machine_web-1_admin_password\n")
+	suite.Contains(hcl, "administrator_login_password = var.azurerm_sql_server_db_password\n")
+	suite.NotContains(hcl, "secret")
+	suite.Contains(hcl, "import {\n  to = azurerm_resource_group.rg\n  id = \"/subscriptions/s-1/resourceGroups/rg\"\n}\n")
+}
+
+// TestNewUnsupported : Testing the export of a graph of an unsupported provider
+func (suite *TerraformTestSuite) TestNewUnsupported() {
+	_, err := terraform.New(graph.New(), "vcloud")
+	suite.NotNil(err)
+	suite.False(terraform.Supported("vcloud"))
+}
+
+// TestTerraformTestSuite : Test suite for terraform exports
+func TestTerraformTestSuite(t *testing.T) {
+	suite.Run(t, new(TerraformTestSuite))
+}
@@ -0,0 +1,151 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ernestio/definition-mapper/libmapper"
+	"github.com/ernestio/definition-mapper/logger"
+	"github.com/nats-io/go-nats"
+)
+
+const (
+	// DEFAULTQUEUE : the queue group replicas share requests on
+	DEFAULTQUEUE = "definition-mapper"
+	// DEFAULTWORKERS : the number of requests handled at once by a single process
+	DEFAULTWORKERS = 10
+	// DEFAULTTIMEOUT : the time a request can take before a timeout error is returned
+	DEFAULTTIMEOUT = time.Second * 30
+	// DEFAULTPENDING : the number of requests a subscription holds while all workers are busy
+	DEFAULTPENDING = 100
+)
+
+var (
+	queue   = DEFAULTQUEUE
+	workers = make(chan struct{}, DEFAULTWORKERS)
+	timeout = DEFAULTTIMEOUT
+	pending = DEFAULTPENDING
+)
+
+// messageHandler : handles a message, returning the provider it was for
+// along with the encoded response. The context is cancelled once the request
+// times out, so the handler can stop and free its worker
+type messageHandler func(context.Context, *nats.Msg) (string, []byte, error)
+
+type result struct {
+	provider string
+	data     []byte
+	err      error
+	timedOut bool
+}
+
+// configureWorkers : sets the queue group, worker pool size, request
+// timeout and pending limit from NATS_QUEUE, WORKERS, REQUEST_TIMEOUT and
+// PENDING_LIMIT
+func configureWorkers() {
+	if q := os.Getenv("NATS_QUEUE"); q != "" {
+		queue = q
+	}
+
+	if w := os.Getenv("WORKERS"); w != "" {
+		size, err := strconv.Atoi(w)
+		if err != nil || size < 1 {
+			logger.Warn("invalid WORKERS value, using the default", logger.Fields{"value": w})
+		} else {
+			workers = make(chan struct{}, size)
+		}
+	}
+
+	if t := os.Getenv("REQUEST_TIMEOUT"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			logger.Warn("invalid REQUEST_TIMEOUT value, using the default", logger.Fields{"value": t})
+		} else {
+			timeout = d
+		}
+	}
+
+	if p := os.Getenv("PENDING_LIMIT"); p != "" {
+		limit, err := strconv.Atoi(p)
+		if err != nil || limit < 1 {
+			logger.Warn("invalid PENDING_LIMIT value, using the default", logger.Fields{"value": p})
+		} else {
+			pending = limit
+		}
+	}
+}
+
+// serve : wraps a message handler so messages are handled in parallel on the
+// worker pool. Once all workers are busy, delivery of the subscription's
+// messages waits for one to become free, while the nats client holds up to
+// the pending limit of them
+func serve(h messageHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		dispatch(context.Background(), h, msg, func(r result) {
+			response(msg.Reply, &r.data, &r.err)
+		})
+	}
+}
+
+// dispatch : handles a message on the worker pool, waiting for a worker to
+// become free. A timeout error is passed to done if the handler does not
+// complete in time, and its context is cancelled so it stops at its next
+// check, freeing its worker
+func dispatch(parent context.Context, h messageHandler, msg *nats.Msg, done func(result)) {
+	start := time.Now()
+
+	inflight.Add(1)
+	workers <- struct{}{}
+
+	ctx, cancel := requestContext(parent)
+	results := make(chan result, 1)
+
+	go func() {
+		defer func() { <-workers }()
+
+		provider, data, err := h(ctx, msg)
+		results <- result{provider: provider, data: data, err: err}
+	}()
+
+	go func() {
+		defer inflight.Done()
+		defer cancel()
+
+		r := wait(ctx, results)
+		if r.timedOut {
+			logger.Warn("request timed out", logger.Fields{"subject": msg.Subject, "timeout": timeout.String()})
+		}
+
+		observe(msg.Subject, r.provider, start, r.err)
+		done(r)
+	}()
+}
+
+// requestContext : returns the context of a request, which is done once the
+// request times out or its parent is done. A timeout of 0 never times out
+func requestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+
+	return context.WithTimeout(parent, timeout)
+}
+
+// wait : waits for the result of a handler, until the request's context is done
+func wait(ctx context.Context, results chan result) result {
+	select {
+	case r := <-results:
+		return r
+	case <-ctx.Done():
+		if ctx.Err() == context.Canceled {
+			return result{err: libmapper.NewError(libmapper.ErrCodeInvalidRequest, "request was cancelled")}
+		}
+		return result{err: libmapper.NewError(libmapper.ErrCodeTimeout, "request did not complete within "+timeout.String()), timedOut: true}
+	}
+}